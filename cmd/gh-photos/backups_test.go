@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantbirki/gh-photos/internal/backup"
+)
+
+func TestWriteBackupList(t *testing.T) {
+	backups := []backup.BackupSummary{
+		{
+			Path:           "/MobileSync/Backup/00008110-000A",
+			UDID:           "00008110-000A",
+			DeviceName:     "Work iPhone",
+			ProductType:    "iPhone15,2",
+			IOSVersion:     "17.5.1",
+			LastBackupDate: time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC),
+			Encrypted:      true,
+			Size:           2048,
+		},
+		{Path: "/MobileSync/Backup/00008110-000B", UDID: "00008110-000B"},
+	}
+
+	var table bytes.Buffer
+	if err := writeBackupList(&table, "/MobileSync", backups, "table"); err != nil {
+		t.Fatalf("writeBackupList table: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if !strings.HasPrefix(lines[0], "DEVICE") {
+		t.Errorf("expected header row, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "Work iPhone") || !strings.Contains(lines[1], "yes") || !strings.Contains(lines[1], "2.0 KB") {
+		t.Errorf("unexpected row for the first backup: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "00008110-000B  -") {
+		t.Errorf("expected missing details shown as '-': %q", lines[2])
+	}
+	if lines[len(lines)-1] != "Total: 2 backups" {
+		t.Errorf("unexpected total line %q", lines[len(lines)-1])
+	}
+
+	var out bytes.Buffer
+	if err := writeBackupList(&out, "/MobileSync", backups, "json"); err != nil {
+		t.Fatalf("writeBackupList json: %v", err)
+	}
+	var decoded []backup.BackupSummary
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].UDID != "00008110-000A" || !decoded[0].Encrypted {
+		t.Errorf("unexpected JSON output: %s", out.String())
+	}
+
+	var empty bytes.Buffer
+	if err := writeBackupList(&empty, "/MobileSync", nil, "json"); err != nil {
+		t.Fatalf("writeBackupList empty: %v", err)
+	}
+	if strings.TrimSpace(empty.String()) != "[]" {
+		t.Errorf("expected an empty JSON array, got %q", empty.String())
+	}
+}
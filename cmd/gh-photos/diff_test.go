@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantbirki/gh-photos/internal/diff"
+	"github.com/grantbirki/gh-photos/internal/types"
+)
+
+func TestWriteDiff(t *testing.T) {
+	created := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	result := diff.Compare("/backups/old", "/backups/new",
+		[]*types.Asset{{ID: "1", UUID: "A", Filename: "IMG_0001.HEIC"}},
+		[]*types.Asset{{ID: "2", UUID: "B", Filename: "IMG_0002.HEIC", Type: types.AssetTypePhoto, CreationDate: created, FileSize: 2048}})
+
+	var table bytes.Buffer
+	if err := writeDiff(&table, result, "table"); err != nil {
+		t.Fatalf("writeDiff table: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if !strings.HasPrefix(lines[0], "CHANGE") {
+		t.Errorf("expected header row, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "added") || !strings.Contains(lines[1], "2024-06-01 12:30") || !strings.Contains(lines[1], "2.0 KB") {
+		t.Errorf("unexpected row for the added asset: %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "deleted") || !strings.Contains(lines[2], "IMG_0001.HEIC") {
+		t.Errorf("unexpected row for the deleted asset: %q", lines[2])
+	}
+	if lines[len(lines)-1] != "Added: 1, Deleted: 1, Hidden: 0, Trashed: 0, Edited: 0" {
+		t.Errorf("unexpected summary line %q", lines[len(lines)-1])
+	}
+
+	var out bytes.Buffer
+	if err := writeDiff(&out, result, "json"); err != nil {
+		t.Fatalf("writeDiff json: %v", err)
+	}
+	var decoded diff.Result
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if selection := decoded.SyncSelection(); len(selection) != 1 || !selection["B"] {
+		t.Errorf("expected the added asset to be selected for sync, got %v", selection)
+	}
+
+	var empty bytes.Buffer
+	if err := writeDiff(&empty, diff.Compare("a", "b", nil, nil), "table"); err != nil {
+		t.Fatalf("writeDiff empty: %v", err)
+	}
+	if !strings.Contains(empty.String(), "No changes between a and b") {
+		t.Errorf("unexpected output for no changes: %q", empty.String())
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantbirki/gh-photos/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func listTestAssets() []*types.Asset {
+	return []*types.Asset{
+		{
+			ID:           "2",
+			Filename:     "IMG_0002.MOV",
+			Type:         types.AssetTypeVideo,
+			CreationDate: time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC),
+			FileSize:     2048,
+			TargetPath:   "2024/03/18/videos/IMG_0002.MOV",
+		},
+		{
+			ID:           "1",
+			Filename:     "IMG_0001.HEIC",
+			Type:         types.AssetTypePhoto,
+			CreationDate: time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC),
+			FileSize:     1024,
+			Flags:        types.AssetFlags{Hidden: true, Edited: true},
+			TargetPath:   "2024/03/18/photos/IMG_0001.HEIC",
+		},
+		{
+			ID:           "3",
+			Filename:     "IMG_0003.HEIC",
+			Type:         types.AssetTypePhoto,
+			CreationDate: time.Date(2024, 3, 19, 8, 0, 0, 0, time.UTC),
+			FileSize:     512,
+			Flags:        types.AssetFlags{Favorite: true},
+			TargetPath:   "2024/03/19/photos/IMG_0003.HEIC",
+		},
+	}
+}
+
+func TestWriteAssetListJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeAssetList(&buf, listTestAssets(), listOptions{Format: "json", SortBy: "date"})
+	assert.NoError(t, err)
+
+	var entries []listEntry
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entries))
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, []string{"1", "2", "3"}, []string{entries[0].ID, entries[1].ID, entries[2].ID})
+		assert.Equal(t, []string{"hidden", "edited"}, entries[0].Flags)
+		assert.Equal(t, []string{"favorite"}, entries[2].Flags)
+		assert.Equal(t, "2024/03/18/photos/IMG_0001.HEIC", entries[0].TargetPath)
+	}
+}
+
+func TestWriteAssetListGroupedByType(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeAssetList(&buf, listTestAssets(), listOptions{Format: "json", SortBy: "date", GroupBy: "type"})
+	assert.NoError(t, err)
+
+	var groups []listGroup
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &groups))
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, "photos", groups[0].Group)
+		assert.Len(t, groups[0].Assets, 2)
+		assert.Equal(t, "videos", groups[1].Group)
+		assert.Len(t, groups[1].Assets, 1)
+	}
+}
+
+func TestWriteAssetListCSVGroupedByDate(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeAssetList(&buf, listTestAssets(), listOptions{Format: "csv", SortBy: "type", GroupBy: "date"})
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, []string{
+		"group,id,filename,type,creation_date,size_bytes,flags,target_path",
+		"2024-03-18,1,IMG_0001.HEIC,photos,2024-03-18T09:00:00Z,1024,hidden;edited,2024/03/18/photos/IMG_0001.HEIC",
+		"2024-03-18,2,IMG_0002.MOV,videos,2024-03-18T12:00:00Z,2048,,2024/03/18/videos/IMG_0002.MOV",
+		"2024-03-19,3,IMG_0003.HEIC,photos,2024-03-19T08:00:00Z,512,favorite,2024/03/19/photos/IMG_0003.HEIC",
+	}, lines)
+}
+
+func TestWriteAssetListTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeAssetList(&buf, listTestAssets(), listOptions{Format: "table", SortBy: "date", GroupBy: "date"})
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "2024-03-18 (2 assets)")
+	assert.Contains(t, output, "TARGET PATH")
+	assert.Contains(t, output, "2024/03/19/photos/IMG_0003.HEIC")
+	assert.Contains(t, output, "Total: 3 assets")
+}
+
+func TestValidateListOptions(t *testing.T) {
+	opts := listOptions{Format: "CSV", SortBy: "Type", GroupBy: " date "}
+	assert.NoError(t, validateListOptions(&opts))
+	assert.Equal(t, listOptions{Format: "csv", SortBy: "type", GroupBy: "date"}, opts)
+
+	assert.Error(t, validateListOptions(&listOptions{Format: "xml", SortBy: "date"}))
+	assert.Error(t, validateListOptions(&listOptions{Format: "table", SortBy: "size"}))
+	assert.Error(t, validateListOptions(&listOptions{Format: "table", SortBy: "date", GroupBy: "album"}))
+}
@@ -2,21 +2,30 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/grantbirki/gh-photos/internal/audit"
 	"github.com/grantbirki/gh-photos/internal/backup"
+	"github.com/grantbirki/gh-photos/internal/diff"
+	"github.com/grantbirki/gh-photos/internal/geo"
 	"github.com/grantbirki/gh-photos/internal/logger"
 	"github.com/grantbirki/gh-photos/internal/photos"
+	"github.com/grantbirki/gh-photos/internal/plist"
 	"github.com/grantbirki/gh-photos/internal/types"
 	"github.com/grantbirki/gh-photos/internal/uploader"
 	"github.com/grantbirki/gh-photos/internal/utils"
@@ -35,8 +44,9 @@ type CommandMetadata struct {
 
 // ExtractionMetadata contains comprehensive metadata for extracted backups
 type ExtractionMetadata struct {
-	CommandMetadata *CommandMetadata `json:"command_metadata"`
-	Assets          []*types.Asset   `json:"assets"`
+	CommandMetadata *CommandMetadata  `json:"command_metadata"`
+	Assets          []*types.Asset    `json:"assets"`
+	EscapedPaths    map[string]string `json:"escaped_paths,omitempty"` // original Domain/relativePath -> escaped path on disk
 }
 
 // SystemInfo contains information about the system running the CLI
@@ -49,15 +59,19 @@ type SystemInfo struct {
 
 // IOSBackupInfo contains information about the iOS backup
 type IOSBackupInfo struct {
-	BackupPath  string  `json:"backup_path"`
-	DeviceName  *string `json:"device_name,omitempty"`
-	DeviceModel *string `json:"device_model,omitempty"`
-	DeviceUUID  *string `json:"device_uuid,omitempty"`
-	IOSVersion  *string `json:"ios_version,omitempty"`
-	BackupDate  *string `json:"backup_date,omitempty"`
-	BackupType  string  `json:"backup_type"` // "hashed" or "directory"
-	IsEncrypted bool    `json:"is_encrypted"`
-	TotalFiles  *int64  `json:"total_files,omitempty"`
+	BackupPath            string   `json:"backup_path"`
+	DeviceName            *string  `json:"device_name,omitempty"`
+	DeviceModel           *string  `json:"device_model,omitempty"`
+	DeviceUUID            *string  `json:"device_uuid,omitempty"`
+	SerialNumber          *string  `json:"serial_number,omitempty"`
+	IOSVersion            *string  `json:"ios_version,omitempty"`
+	BuildVersion          *string  `json:"build_version,omitempty"`
+	BackupDate            *string  `json:"backup_date,omitempty"`
+	LastBackupDate        *string  `json:"last_backup_date,omitempty"`
+	BackupType            string   `json:"backup_type"` // "hashed" or "directory"
+	IsEncrypted           bool     `json:"is_encrypted"`
+	TotalFiles            *int64   `json:"total_files,omitempty"`
+	InstalledApplications []string `json:"installed_applications,omitempty"`
 }
 
 // AssetCounts contains counts of different asset types
@@ -68,6 +82,9 @@ type AssetCounts struct {
 	Screenshots int `json:"screenshots"`
 	Burst       int `json:"burst"`
 	Total       int `json:"total"`
+
+	// Photo and video subtypes, also counted in Photos and Videos
+	Subtypes map[string]int `json:"subtypes,omitempty"`
 }
 
 func main() {
@@ -134,6 +151,8 @@ The tool supports:
 	cmd.AddCommand(CreateValidateCommand())
 	cmd.AddCommand(CreateListCommand())
 	cmd.AddCommand(CreateExtractCommand())
+	cmd.AddCommand(CreateBackupsCommand())
+	cmd.AddCommand(CreateDiffCommand())
 
 	return cmd
 }
@@ -151,7 +170,9 @@ an rClone remote in an organized folder structure.
 Examples:
   gh photos sync /path/to/backup gdrive:photos/backup/path
   gh photos sync /backup/iphone s3:mybucket/photos --dry-run
-  gh photos sync /backup gdrive:photos --include-hidden --parallel 8`,
+  gh photos sync /backup gdrive:photos --include-hidden --parallel 8
+  gh photos sync /backup gdrive:photos --resume sync-manifest.json
+  gh photos sync ~/Library/Application\ Support/MobileSync gdrive:photos --device "Jane's iPhone"`,
 		Args:         cobra.ExactArgs(2),
 		SilenceUsage: true,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -178,6 +199,7 @@ Examples:
 	cmd.Flags().BoolVar(&config.IncludeRecentlyDeleted, "include-recently-deleted", false, "include assets flagged as recently deleted")
 	cmd.Flags().BoolVar(&config.DryRun, "dry-run", false, "preview operations without uploading")
 	cmd.Flags().BoolVar(&config.SkipExisting, "skip-existing", true, "skip files that already exist on remote")
+	cmd.Flags().BoolVar(&config.RemotePreScan, "remote-pre-scan", false, "list the remote before uploading so existing files are planned as skips (slower)")
 	var forceOverwrite bool
 	cmd.Flags().BoolVar(&forceOverwrite, "force-overwrite", false, "overwrite existing files on remote (opposite of --skip-existing)")
 	cmd.Flags().BoolVar(&config.Verify, "verify", false, "verify uploaded files match source")
@@ -186,17 +208,40 @@ Examples:
 	var batchTimeoutStr string
 	cmd.Flags().StringVar(&batchTimeoutStr, "batch-timeout", "30m", "timeout for individual batch uploads (e.g., 30m, 1h)")
 	cmd.Flags().StringVar(&config.SaveManifest, "save-manifest", "", "path to save operation manifest (JSON)")
+	cmd.Flags().StringVar(&config.Resume, "resume", "", "resume an interrupted sync from its saved manifest (JSON), retrying entries not yet uploaded or verified")
 	cmd.Flags().StringVar(&config.SaveAuditManifest, "save-audit-manifest", "", "path to save an additional copy of the audit trail manifest (JSON)")
 	cmd.Flags().BoolVar(&config.UseLastCommand, "use-last-command", false, "re-run the last successful command from ~/gh-photos/manifest.json")
-	cmd.Flags().StringSliceVar(&config.AssetTypes, "types", nil, "comma-separated asset types to include (photos,videos,screenshots,burst,live_photos)")
+	cmd.Flags().StringSliceVar(&config.AssetTypes, "types", nil, "comma-separated asset types to include (photos,videos,screenshots,burst,live_photos, or a subtype: portrait,panorama,raw,slomo,timelapse,cinematic,screen_recording)")
 	cmd.Flags().StringSliceVar(&config.IgnorePatterns, "ignore", nil, "patterns to ignore (supports wildcards and directory names like 'PhotoData')")
 	cmd.Flags().StringVar(&config.PathGranularity, "path-granularity", "day", "date path depth: year, month, or day (default: day)")
+	cmd.Flags().StringVar(&config.PathTemplate, "path-template", "", "remote path template, e.g. '{year}/{year}-{month} {monthname}/{filename}' (overrides --path-granularity)")
+	cmd.Flags().StringSliceVar(&config.Albums, "albums", nil, "comma-separated album names to include (assets in any listed album)")
+	cmd.Flags().StringSliceVar(&config.People, "person", nil, "only include assets in which this person or pet is recognized (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&config.FavoritesOnly, "favorites-only", false, "only include assets marked as favorites in the Photos app")
+	cmd.Flags().StringVar(&config.Near, "near", "", "only include assets captured within a radius of a point: 'lat,lon,radius' (km, or add 'mi'), e.g. '39.74,-104.99,25'")
+	cmd.Flags().StringVar(&config.Gazetteer, "gazetteer", "", "GeoNames cities file (e.g. cities15000.txt) used for {country}/{city} instead of the embedded gazetteer")
+	cmd.Flags().BoolVar(&config.OrganizeByAlbum, "organize-by-album", false, "place assets under an <album>/ folder on the remote before the date path")
+	cmd.Flags().StringVar(&config.LivePhotoMode, "live-photo-mode", "both", "which part of a Live Photo to upload: both, still, or video")
+	cmd.Flags().StringVar(&config.BurstMode, "burst-mode", "all", "which frames of a burst to upload: all, picked (the frames you selected), or key (the key frame)")
+	cmd.Flags().StringVar(&config.EditsMode, "edits", "original", "for edited assets upload the original, the edited render, or both (original, edited, both)")
+	cmd.Flags().StringVar(&config.Timezone, "timezone", "", "timezone for assets without a recorded capture timezone (e.g. America/Denver, -07:00; default: UTC)")
+	cmd.Flags().StringVar(&config.FavoritesRemote, "favorites-remote", "", "also copy favorites to this second rclone remote (e.g. a shared family album)")
+	cmd.Flags().StringVar(&config.AssetList, "asset-list", "", "only sync the assets added or edited in a 'gh photos diff --format json' file")
+	cmd.Flags().StringVar(&config.FilenameTemplate, "filename-template", "", "rename files on upload, e.g. '{date:20060102_150405}_{original}' (tokens: original, base, filename, ext, id, date)")
 
 	// Date filter flags
 	var startDateStr, endDateStr string
 	cmd.Flags().StringVar(&startDateStr, "start-date", "", "start date filter (YYYY-MM-DD)")
 	cmd.Flags().StringVar(&endDateStr, "end-date", "", "end date filter (YYYY-MM-DD)")
 
+	// Encrypted backup password
+	var passwordFile string
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "file containing the password of an encrypted backup (default: $GH_PHOTOS_BACKUP_PASSWORD)")
+
+	// Backup selection when the backup path holds several device backups
+	var device string
+	cmd.Flags().StringVar(&device, "device", "", "pick the backup of this device (name or UDID) when the backup path holds several backups")
+
 	// Custom PreRunE to handle configuration setup
 	originalPreRunE := cmd.PreRunE
 	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
@@ -206,7 +251,22 @@ Examples:
 			}
 		}
 
-		return configureSyncCommand(&config, cmd, args, forceOverwrite, startDateStr, endDateStr)
+		if err := configureSyncCommand(&config, cmd, args, forceOverwrite, startDateStr, endDateStr); err != nil {
+			return err
+		}
+
+		backupPath, err := selectDeviceBackup(config.BackupPath, device)
+		if err != nil {
+			return err
+		}
+		config.BackupPath = backupPath
+
+		password, err := resolveBackupPassword(passwordFile)
+		if err != nil {
+			return err
+		}
+		config.Password = password
+		return nil
 	}
 
 	return cmd
@@ -249,6 +309,44 @@ func configureSyncCommand(config *uploader.Config, cmd *cobra.Command, args []st
 		return err
 	}
 
+	// Normalize and validate Live Photo mode
+	if err := validateLivePhotoMode(config); err != nil {
+		return err
+	}
+
+	// Normalize and validate burst mode
+	if err := validateBurstMode(config); err != nil {
+		return err
+	}
+
+	// Normalize and validate edits mode
+	if err := validateEditsMode(config); err != nil {
+		return err
+	}
+
+	// Validate timezone override
+	if err := validateTimezone(config); err != nil {
+		return err
+	}
+
+	// Validate the asset list from a backup diff
+	if err := validateAssetList(config); err != nil {
+		return err
+	}
+
+	// Validate the location filter and gazetteer
+	if err := validateNear(config); err != nil {
+		return err
+	}
+	if err := validateGazetteer(config); err != nil {
+		return err
+	}
+
+	// Validate the favorites mirror
+	if err := validateFavoritesRemote(config); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -325,7 +423,7 @@ func configureBatchTimeout(config *uploader.Config, cmd *cobra.Command) error {
 	return nil
 }
 
-// validatePathGranularity handles path granularity normalization and validation
+// validatePathGranularity handles path granularity normalization and validation, including --path-template and --filename-template
 func validatePathGranularity(config *uploader.Config) error {
 	normalized := utils.NormalizeString(config.PathGranularity)
 	if normalized == "" {
@@ -338,11 +436,145 @@ func validatePathGranularity(config *uploader.Config) error {
 	}
 
 	config.PathGranularity = normalized
+
+	// Validate filename template
+	if config.FilenameTemplate != "" {
+		if err := types.ValidateFilenameTemplate(config.FilenameTemplate); err != nil {
+			return fmt.Errorf("invalid filename template: %w", err)
+		}
+	}
+
+	// A path template replaces the granularity-based layout entirely
+	if config.PathTemplate != "" {
+		if err := types.ValidatePathTemplate(config.PathTemplate); err != nil {
+			return fmt.Errorf("invalid path template: %w", err)
+		}
+		if config.OrganizeByAlbum {
+			return fmt.Errorf("--organize-by-album cannot be combined with --path-template; use the {album} token instead")
+		}
+	}
+	return nil
+}
+
+// validateTimezone validates the --timezone override
+func validateTimezone(config *uploader.Config) error {
+	if config.Timezone == "" {
+		return nil
+	}
+	if _, err := utils.ParseTimezone(config.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	return nil
+}
+
+// validateAssetList checks that the --asset-list file is a readable diff
+func validateAssetList(config *uploader.Config) error {
+	if config.AssetList == "" {
+		return nil
+	}
+	if _, err := diff.LoadFromFile(config.AssetList); err != nil {
+		return fmt.Errorf("invalid asset list: %w", err)
+	}
+	return nil
+}
+
+// validateNear validates the --near area
+func validateNear(config *uploader.Config) error {
+	if config.Near == "" {
+		return nil
+	}
+	if _, err := geo.ParseArea(config.Near); err != nil {
+		return fmt.Errorf("invalid --near: %w", err)
+	}
+	return nil
+}
+
+// validateGazetteer checks that the --gazetteer file is a readable GeoNames cities file
+func validateGazetteer(config *uploader.Config) error {
+	if config.Gazetteer == "" {
+		return nil
+	}
+	if _, err := geo.LoadGeoNames(config.Gazetteer); err != nil {
+		return fmt.Errorf("invalid gazetteer: %w", err)
+	}
+	return nil
+}
+
+// validateFavoritesRemote checks that favorites are mirrored to a remote other than the main one
+func validateFavoritesRemote(config *uploader.Config) error {
+	if config.FavoritesRemote == "" {
+		return nil
+	}
+	if strings.TrimSuffix(config.FavoritesRemote, "/") == strings.TrimSuffix(config.Remote, "/") {
+		return fmt.Errorf("--favorites-remote must differ from the sync remote %s", config.Remote)
+	}
+	return nil
+}
+
+// validateLivePhotoMode handles Live Photo mode normalization and validation
+func validateLivePhotoMode(config *uploader.Config) error {
+	normalized := utils.NormalizeString(config.LivePhotoMode)
+	if normalized == "" {
+		normalized = string(types.LivePhotoModeBoth)
+	}
+
+	validModes := map[string]bool{
+		string(types.LivePhotoModeBoth):  true,
+		string(types.LivePhotoModeStill): true,
+		string(types.LivePhotoModeVideo): true,
+	}
+	if !validModes[normalized] {
+		return fmt.Errorf("invalid live photo mode '%s'. Valid values: both, still, video", config.LivePhotoMode)
+	}
+
+	config.LivePhotoMode = normalized
+	return nil
+}
+
+// validateBurstMode handles burst mode normalization and validation
+func validateBurstMode(config *uploader.Config) error {
+	normalized := utils.NormalizeString(config.BurstMode)
+	if normalized == "" {
+		normalized = string(types.BurstModeAll)
+	}
+
+	validModes := map[string]bool{
+		string(types.BurstModeAll):    true,
+		string(types.BurstModePicked): true,
+		string(types.BurstModeKey):    true,
+	}
+	if !validModes[normalized] {
+		return fmt.Errorf("invalid burst mode '%s'. Valid values: all, picked, key", config.BurstMode)
+	}
+
+	config.BurstMode = normalized
+	return nil
+}
+
+// validateEditsMode handles edits mode normalization and validation
+func validateEditsMode(config *uploader.Config) error {
+	normalized := utils.NormalizeString(config.EditsMode)
+	if normalized == "" {
+		normalized = string(types.EditsModeOriginal)
+	}
+
+	validModes := map[string]bool{
+		string(types.EditsModeOriginal): true,
+		string(types.EditsModeEdited):   true,
+		string(types.EditsModeBoth):     true,
+	}
+	if !validModes[normalized] {
+		return fmt.Errorf("invalid edits mode '%s'. Valid values: original, edited, both", config.EditsMode)
+	}
+
+	config.EditsMode = normalized
 	return nil
 }
 
 // CreateValidateCommand creates the validate subcommand
 func CreateValidateCommand() *cobra.Command {
+	var device string
+
 	cmd := &cobra.Command{
 		Use:   "validate [backup-path]",
 		Short: "Validate an iPhone backup directory",
@@ -362,31 +594,84 @@ If no path is provided, checks the current working directory.`,
 			} else {
 				backupPath = args[0]
 			}
+
+			backupPath, err := selectDeviceBackup(backupPath, device)
+			if err != nil {
+				return err
+			}
 			return runValidate(backupPath)
 		},
 	}
 
+	cmd.Flags().StringVar(&device, "device", "", "pick the backup of this device (name or UDID) when the backup path holds several backups")
+
 	return cmd
 }
 
 // CreateListCommand creates the list subcommand
 func CreateListCommand() *cobra.Command {
+	var (
+		config                   uploader.Config
+		opts                     listOptions
+		startDateStr, endDateStr string
+		passwordFile             string
+	)
+
 	cmd := &cobra.Command{
 		Use:   "list <backup-path>",
 		Short: "List assets found in an iPhone backup",
 		Long: `List parses the iPhone backup and displays information about found assets
-including their classification, flags, and file locations.`,
+including their classification, flags, and the target path sync would upload them to.
+
+The same filters as sync are applied, so list shows exactly what sync would upload.
+
+Examples:
+  gh photos list /path/to/backup
+  gh photos list /path/to/backup --types photos,videos --group-by date
+  gh photos list /path/to/backup --format csv > assets.csv
+  gh photos list /path/to/backup --format json --sort type`,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Disable colors if requested
+			if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+				color.NoColor = true
+			}
+
+			config.BackupPath = args[0]
+			password, err := resolveBackupPassword(passwordFile)
+			if err != nil {
+				return err
+			}
+			config.Password = password
+			return configureListCommand(&config, &opts, cmd, startDateStr, endDateStr)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(args[0])
+			return runList(config, opts)
 		},
 	}
 
-	cmd.Flags().Bool("include-hidden", false, "include hidden assets in listing")
-	cmd.Flags().Bool("include-recently-deleted", false, "include recently deleted assets in listing")
-	cmd.Flags().StringSlice("types", nil, "filter by asset types")
-	cmd.Flags().String("format", "table", "output format (table, json)")
+	cmd.Flags().BoolVar(&config.IncludeHidden, "include-hidden", false, "include hidden assets in listing")
+	cmd.Flags().BoolVar(&config.IncludeRecentlyDeleted, "include-recently-deleted", false, "include recently deleted assets in listing")
+	cmd.Flags().StringSliceVar(&config.AssetTypes, "types", nil, "comma-separated asset types to include (photos,videos,screenshots,burst,live_photos, or a subtype: portrait,panorama,raw,slomo,timelapse,cinematic,screen_recording)")
+	cmd.Flags().StringSliceVar(&config.IgnorePatterns, "ignore", nil, "patterns to ignore (supports wildcards and directory names like 'PhotoData')")
+	cmd.Flags().StringSliceVar(&config.Albums, "albums", nil, "comma-separated album names to include (assets in any listed album)")
+	cmd.Flags().StringSliceVar(&config.People, "person", nil, "only include assets in which this person or pet is recognized (repeatable or comma-separated)")
+	cmd.Flags().BoolVar(&config.FavoritesOnly, "favorites-only", false, "only include assets marked as favorites in the Photos app")
+	cmd.Flags().StringVar(&config.Near, "near", "", "only include assets captured within a radius of a point: 'lat,lon,radius' (km, or add 'mi'), e.g. '39.74,-104.99,25'")
+	cmd.Flags().StringVar(&config.Gazetteer, "gazetteer", "", "GeoNames cities file (e.g. cities15000.txt) used for {country}/{city} instead of the embedded gazetteer")
+	cmd.Flags().StringVar(&startDateStr, "start-date", "", "start date filter (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&endDateStr, "end-date", "", "end date filter (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&config.Timezone, "timezone", "", "timezone for assets without a recorded capture timezone (e.g. America/Denver, -07:00; default: UTC)")
+	cmd.Flags().StringVar(&config.PathGranularity, "path-granularity", "day", "date path depth used for target paths: year, month, or day")
+	cmd.Flags().StringVar(&config.PathTemplate, "path-template", "", "remote path template used for target paths (overrides --path-granularity)")
+	cmd.Flags().StringVar(&config.FilenameTemplate, "filename-template", "", "filename template used for target paths")
+	cmd.Flags().BoolVar(&config.OrganizeByAlbum, "organize-by-album", false, "prefix target paths with the asset's album")
+	cmd.Flags().StringVar(&config.BurstMode, "burst-mode", "all", "which frames of a burst to list: all, picked, or key")
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "output format (table, json, csv)")
+	cmd.Flags().StringVar(&opts.SortBy, "sort", "date", "sort assets by date or type")
+	cmd.Flags().StringVar(&opts.GroupBy, "group-by", "", "group assets by date or type")
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "file containing the password of an encrypted backup (default: $GH_PHOTOS_BACKUP_PASSWORD)")
 
 	return cmd
 }
@@ -398,26 +683,47 @@ func CreateExtractCommand() *cobra.Command {
 		skipExisting bool
 		verify       bool
 		progress     bool
+		passwordFile string
+		workers      int
+		resume       bool
+		domains      []string
+		include      []string
+		exclude      []string
+		mediaOnly    bool
+		device       string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "extract <backup-path> [output-path]",
-		Short: "Extract unencrypted iTunes/Finder backup to readable directory structure",
-		Long: `Extract reconstructs the original directory structure from an unencrypted iTunes or Finder backup.
+		Short: "Extract iTunes/Finder backup to readable directory structure",
+		Long: `Extract reconstructs the original directory structure from an iTunes or Finder backup.
 
 This command reads the Manifest.db file to map hashed backup files back to their original
 paths and domains, creating a readable directory structure similar to the original device.
 
-Only unencrypted backups are supported. Encrypted backups will be rejected with an error.
+Encrypted backups are decrypted on the fly. Provide the backup password with --password-file
+or the GH_PHOTOS_BACKUP_PASSWORD environment variable.
+
+Use --domain, --include and --exclude to extract part of a backup. Patterns are
+case-sensitive globs and '*' also matches '/'. --media-only extracts just the camera
+roll, PhotoData and the Photos database.
 
 Examples:
   gh photos extract /path/to/backup
   gh photos extract /backup/iPhone ./extracted --skip-existing
-  gh photos extract /backup ./extracted --verify --progress`,
+  gh photos extract /backup ./extracted --verify --progress
+  gh photos extract /backup ./extracted --workers 8 --resume
+  gh photos extract /backup/encrypted ./extracted --password-file ~/.backup-password
+  gh photos extract /backup ./extracted --media-only --exclude '*.AAE'
+  gh photos extract /backup ./extracted --domain 'AppDomain-com.apple.*' --include 'Documents/*'
+  gh photos extract ~/Library/Application\ Support/MobileSync ./extracted --device 00008110-001A2B3C4D5E6F70`,
 		Args:         cobra.RangeArgs(1, 2),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			backupPath := args[0]
+			backupPath, err := selectDeviceBackup(args[0], device)
+			if err != nil {
+				return err
+			}
 
 			// Use provided output path or default
 			if len(args) > 1 {
@@ -427,7 +733,29 @@ Examples:
 				outputPath = "./extracted-backup"
 			}
 
-			return runExtract(backupPath, outputPath, skipExisting, verify, progress)
+			password, err := resolveBackupPassword(passwordFile)
+			if err != nil {
+				return err
+			}
+
+			if workers < 1 {
+				return fmt.Errorf("--workers must be at least 1")
+			}
+
+			return runExtract(backup.ExtractConfig{
+				BackupPath:   backupPath,
+				OutputPath:   outputPath,
+				SkipExisting: skipExisting,
+				Verify:       verify,
+				Progress:     progress,
+				Password:     password,
+				Workers:      workers,
+				Resume:       resume,
+				Domains:      domains,
+				Include:      include,
+				Exclude:      exclude,
+				MediaOnly:    mediaOnly,
+			})
 		},
 	}
 
@@ -436,12 +764,271 @@ Examples:
 	cmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "skip files that already exist in output directory")
 	cmd.Flags().BoolVar(&verify, "verify", false, "verify extracted files by comparing checksums (disabled by default as it significantly slows extraction)")
 	cmd.Flags().BoolVar(&progress, "progress", true, "show extraction progress")
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "file containing the password of an encrypted backup (default: $GH_PHOTOS_BACKUP_PASSWORD)")
+	cmd.Flags().IntVar(&workers, "workers", 4, "number of files to extract concurrently")
+	cmd.Flags().BoolVar(&resume, "resume", false, "continue an interrupted extraction, skipping files recorded in the output directory's extraction journal")
+	cmd.Flags().StringArrayVar(&domains, "domain", nil, "only extract domains matching this glob (repeatable, e.g. CameraRollDomain or 'AppDomain-*')")
+	cmd.Flags().StringArrayVar(&include, "include", nil, "only extract relative paths matching this glob (repeatable, e.g. 'Media/DCIM/*')")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "skip relative paths matching this glob (repeatable, e.g. '*.AAE')")
+	cmd.Flags().BoolVar(&mediaOnly, "media-only", false, "only extract the camera roll, PhotoData and the Photos database")
+	cmd.Flags().StringVar(&device, "device", "", "pick the backup of this device (name or UDID) when the backup path holds several backups")
+
+	return cmd
+}
+
+// CreateBackupsCommand creates the backups subcommand
+func CreateBackupsCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "backups [mobilesync-path]",
+		Short: "List the device backups in a MobileSync directory",
+		Long: `Backups lists every device backup in a MobileSync directory with its UDID, device name,
+model, iOS version, last backup date, encryption state and size, read from Info.plist and
+Manifest.plist. Without a path, the default MobileSync location of this system is scanned.
+
+Pass a backup's device name or UDID to --device to sync, extract or validate it from the
+parent directory.
+
+Examples:
+  gh photos backups
+  gh photos backups ~/Library/Application\ Support/MobileSync
+  gh photos backups /Volumes/External/MobileSync/Backup --format json`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be table or json)", format)
+			}
+
+			var root string
+			if len(args) > 0 {
+				root = args[0]
+			} else {
+				var err error
+				if root, err = defaultMobileSyncRoot(); err != nil {
+					return err
+				}
+			}
+
+			backups, err := backup.DiscoverBackups(root)
+			if err != nil {
+				return err
+			}
+			return writeBackupList(os.Stdout, root, backups, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json)")
+
+	return cmd
+}
+
+// defaultMobileSyncRoot returns the first MobileSync backup directory that exists on this system
+func defaultMobileSyncRoot() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+
+	candidates := []string{filepath.Join(home, "Library", "Application Support", "MobileSync", "Backup")}
+	if runtime.GOOS == "windows" {
+		candidates = []string{filepath.Join(home, "Apple", "MobileSync", "Backup")}
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			candidates = append(candidates, filepath.Join(appData, "Apple Computer", "MobileSync", "Backup"))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no MobileSync backup directory found (looked in %s) - pass the path explicitly", strings.Join(candidates, ", "))
+}
+
+// writeBackupList prints the backups found under root as a table or JSON
+func writeBackupList(w io.Writer, root string, backups []backup.BackupSummary, format string) error {
+	if format == "json" {
+		if backups == nil {
+			backups = []backup.BackupSummary{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(backups)
+	}
+
+	if len(backups) == 0 {
+		fmt.Fprintf(w, "No device backups found in %s\n", root)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DEVICE\tUDID\tMODEL\tIOS\tLAST BACKUP\tENCRYPTED\tSIZE\tPATH")
+	for _, b := range backups {
+		lastBackup := "-"
+		if !b.LastBackupDate.IsZero() {
+			lastBackup = b.LastBackupDate.Local().Format("2006-01-02 15:04")
+		}
+		encrypted := "no"
+		if b.Encrypted {
+			encrypted = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			valueOrDash(b.DeviceName),
+			b.UDID,
+			valueOrDash(b.ProductType),
+			valueOrDash(b.IOSVersion),
+			lastBackup,
+			encrypted,
+			formatBytes(b.Size),
+			b.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nTotal: %d backups\n", len(backups))
+	return nil
+}
+
+// valueOrDash returns value, or "-" when it's empty
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// CreateDiffCommand creates the diff subcommand
+func CreateDiffCommand() *cobra.Command {
+	var format, passwordFile string
+
+	cmd := &cobra.Command{
+		Use:   "diff <backup-a> <backup-b>",
+		Short: "Show what changed in the photo library between two backups",
+		Long: `Diff compares the Photos libraries of two backups of the same device and reports the assets
+added, deleted, newly hidden, moved to Recently Deleted, or edited in backup B since backup A.
+Assets are matched by their Photos UUID, which stays the same across backups, so renamed files
+and re-numbered database rows are not reported as changes.
+
+Save the JSON output and pass it to 'gh photos sync --asset-list' to upload only the added and
+edited assets. Both backups are opened with the same password when they are encrypted.
+
+Examples:
+  gh photos diff /backups/iphone-2024-01 /backups/iphone-2024-06
+  gh photos diff /backups/old /backups/new --format json > changes.json
+  gh photos sync /backups/new gdrive:photos --asset-list changes.json`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be table or json)", format)
+			}
+
+			password, err := resolveBackupPassword(passwordFile)
+			if err != nil {
+				return err
+			}
+
+			before, err := parseBackupAssets(args[0], password)
+			if err != nil {
+				return err
+			}
+			after, err := parseBackupAssets(args[1], password)
+			if err != nil {
+				return err
+			}
+
+			// Database IDs are renumbered between backups, so only UUIDs identify an asset in both
+			if err := diff.RequireUUIDs(args[0], before); err != nil {
+				return err
+			}
+			if err := diff.RequireUUIDs(args[1], after); err != nil {
+				return err
+			}
+
+			return writeDiff(os.Stdout, diff.Compare(args[0], args[1], before, after), format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format (table, json)")
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "file containing the password of encrypted backups (default: $GH_PHOTOS_BACKUP_PASSWORD)")
 
 	return cmd
 }
 
+// parseBackupAssets reads every asset of a backup, logging warnings to stderr so stdout stays parseable
+func parseBackupAssets(backupPath, password string) ([]*types.Asset, error) {
+	log := logger.New(logger.Config{Level: logger.LevelWarn, Output: os.Stderr})
+
+	parser, err := backup.CreateBackupParserWithPassword(backupPath, password, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup %s: %w", backupPath, withPasswordHint(err))
+	}
+	defer parser.Close()
+
+	assets, err := parser.ParseAssets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse assets from %s: %w", backupPath, err)
+	}
+	return assets, nil
+}
+
+// writeDiff writes the changes between two backups as a table or as JSON
+func writeDiff(w io.Writer, result *diff.Result, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	if len(result.Changes) == 0 {
+		fmt.Fprintf(w, "No changes between %s and %s\n", result.BackupA, result.BackupB)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHANGE\tIDENTITY\tFILENAME\tTYPE\tCREATED\tSIZE")
+	for _, change := range result.Changes {
+		created := "-"
+		if !change.CreationDate.IsZero() {
+			created = change.CreationDate.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			change.Change,
+			change.Identity,
+			change.Filename,
+			change.Type,
+			created,
+			formatBytes(change.FileSize))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nAdded: %d, Deleted: %d, Hidden: %d, Trashed: %d, Edited: %d\n",
+		result.Summary[diff.ChangeAdded],
+		result.Summary[diff.ChangeDeleted],
+		result.Summary[diff.ChangeHidden],
+		result.Summary[diff.ChangeTrashed],
+		result.Summary[diff.ChangeEdited])
+	return nil
+}
+
+// selectDeviceBackup resolves --device to the matching backup under backupPath.
+// Returns backupPath unchanged when no device was requested.
+func selectDeviceBackup(backupPath, device string) (string, error) {
+	if device == "" {
+		return backupPath, nil
+	}
+	return backup.SelectBackup(backupPath, device)
+}
+
 // runExtract executes the backup extraction
-func runExtract(backupPath, outputPath string, skipExisting, verify, progress bool) error {
+func runExtract(extractConfig backup.ExtractConfig) error {
+	backupPath := extractConfig.BackupPath
+	outputPath := extractConfig.OutputPath
+
 	// Create logger
 	loggerConfig := logger.Config{
 		Level:  logger.LevelInfo,
@@ -454,18 +1041,11 @@ func runExtract(backupPath, outputPath string, skipExisting, verify, progress bo
 		"output_path", outputPath)
 
 	// Create extractor
-	extractConfig := backup.ExtractConfig{
-		BackupPath:   backupPath,
-		OutputPath:   outputPath,
-		SkipExisting: skipExisting,
-		Verify:       verify,
-		Progress:     progress,
-		Logger:       log,
-	}
+	extractConfig.Logger = log
 
 	extractor, err := backup.CreateExtractor(extractConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create extractor: %w", err)
+		return fmt.Errorf("failed to create extractor: %w", withPasswordHint(err))
 	}
 	defer extractor.Close()
 
@@ -496,13 +1076,20 @@ func runExtract(backupPath, outputPath string, skipExisting, verify, progress bo
 	log.Infof("  Total files processed: %d", summary.TotalFiles)
 	log.Infof("  Files extracted: %d", summary.ExtractedFiles)
 	log.Infof("  Files skipped: %d", summary.SkippedFiles)
+	if summary.ResumedFiles > 0 {
+		log.Infof("  Files already extracted (resumed): %d", summary.ResumedFiles)
+	}
 	if summary.FilesystemCompatErrors > 0 {
 		log.Infof("  Files failed (critical): %d", len(summary.CriticalErrors))
 		log.Infof("  Files skipped (filesystem): %d", summary.FilesystemCompatErrors)
 	} else {
 		log.Infof("  Files failed: %d", summary.FailedFiles)
 	}
+	if len(summary.EscapedPaths) > 0 {
+		log.Infof("  Files with escaped names: %d (recorded in extraction-metadata.json)", len(summary.EscapedPaths))
+	}
 	log.Infof("  Domains found: %d", summary.DomainsFound)
+	log.Infof("  Estimated size: %s", formatBytes(summary.EstimatedSize))
 	log.Infof("  Total size: %s", formatBytes(summary.TotalSize))
 	log.Infof("  Extracted size: %s", formatBytes(summary.ExtractedSize))
 	log.Infof("  Duration: %v", summary.Duration.Round(time.Second))
@@ -519,7 +1106,7 @@ func runExtract(backupPath, outputPath string, skipExisting, verify, progress bo
 
 	// Extract Photos.sqlite data for sync operations
 	var assets []*types.Asset
-	if parser, err := backup.CreateBackupParser(backupPath, logger.New(logger.Config{Level: logger.LevelWarn, Output: os.Stderr})); err == nil {
+	if parser, err := backup.CreateBackupParserWithPassword(backupPath, extractConfig.Password, logger.New(logger.Config{Level: logger.LevelWarn, Output: os.Stderr})); err == nil {
 		if extractedAssets, err := parser.ParseAssetsForExtraction(); err == nil {
 			assets = extractedAssets
 			// Set asset counts for display
@@ -539,6 +1126,7 @@ func runExtract(backupPath, outputPath string, skipExisting, verify, progress bo
 	extractionMetadata := ExtractionMetadata{
 		CommandMetadata: metadata,
 		Assets:          assets,
+		EscapedPaths:    summary.EscapedPaths,
 	}
 
 	manifestPath := filepath.Join(outputPath, "extraction-metadata.json")
@@ -603,7 +1191,13 @@ func (m *CommandMetadata) setAssetCounts(assets []*types.Asset) {
 
 	for _, asset := range assets {
 		counts.Total++
-		switch asset.Type {
+		if subtype := asset.Type.Subtype(); subtype != "" {
+			if counts.Subtypes == nil {
+				counts.Subtypes = make(map[string]int)
+			}
+			counts.Subtypes[subtype]++
+		}
+		switch asset.Type.Base() {
 		case types.AssetTypePhoto:
 			counts.Photos++
 		case types.AssetTypeVideo:
@@ -644,11 +1238,24 @@ func (m *CommandMetadata) printSummary() {
 			fmt.Printf("  Device model: %s\n", *m.IOSBackup.DeviceModel)
 		}
 		if m.IOSBackup.IOSVersion != nil {
-			fmt.Printf("  iOS version: %s\n", *m.IOSBackup.IOSVersion)
+			if m.IOSBackup.BuildVersion != nil {
+				fmt.Printf("  iOS version: %s (%s)\n", *m.IOSBackup.IOSVersion, *m.IOSBackup.BuildVersion)
+			} else {
+				fmt.Printf("  iOS version: %s\n", *m.IOSBackup.IOSVersion)
+			}
+		}
+		if m.IOSBackup.SerialNumber != nil {
+			fmt.Printf("  Serial number: %s\n", *m.IOSBackup.SerialNumber)
 		}
 		if m.IOSBackup.BackupDate != nil {
 			fmt.Printf("  Backup date: %s\n", *m.IOSBackup.BackupDate)
 		}
+		if m.IOSBackup.LastBackupDate != nil {
+			fmt.Printf("  Last backup date: %s\n", *m.IOSBackup.LastBackupDate)
+		}
+		if len(m.IOSBackup.InstalledApplications) > 0 {
+			fmt.Printf("  Installed applications: %d\n", len(m.IOSBackup.InstalledApplications))
+		}
 		if m.IOSBackup.TotalFiles != nil {
 			fmt.Printf("  Total files: %d\n", *m.IOSBackup.TotalFiles)
 		}
@@ -661,6 +1268,14 @@ func (m *CommandMetadata) printSummary() {
 		fmt.Printf("  Live Photos: %d\n", m.AssetCounts.LivePhotos)
 		fmt.Printf("  Screenshots: %d\n", m.AssetCounts.Screenshots)
 		fmt.Printf("  Burst: %d\n", m.AssetCounts.Burst)
+		for _, subtype := range []types.AssetType{
+			types.AssetTypePortrait, types.AssetTypePanorama, types.AssetTypeRaw,
+			types.AssetTypeSlomo, types.AssetTypeTimelapse, types.AssetTypeCinematic, types.AssetTypeScreenRecording,
+		} {
+			if count := m.AssetCounts.Subtypes[string(subtype)]; count > 0 {
+				fmt.Printf("    %s (%s): %d\n", subtype, subtype.Base(), count)
+			}
+		}
 		fmt.Printf("  Total: %d\n", m.AssetCounts.Total)
 	}
 }
@@ -689,11 +1304,24 @@ func (m *CommandMetadata) printSummaryForExtract() {
 			fmt.Printf("  Device model: %s\n", *m.IOSBackup.DeviceModel)
 		}
 		if m.IOSBackup.IOSVersion != nil {
-			fmt.Printf("  iOS version: %s\n", *m.IOSBackup.IOSVersion)
+			if m.IOSBackup.BuildVersion != nil {
+				fmt.Printf("  iOS version: %s (%s)\n", *m.IOSBackup.IOSVersion, *m.IOSBackup.BuildVersion)
+			} else {
+				fmt.Printf("  iOS version: %s\n", *m.IOSBackup.IOSVersion)
+			}
+		}
+		if m.IOSBackup.SerialNumber != nil {
+			fmt.Printf("  Serial number: %s\n", *m.IOSBackup.SerialNumber)
 		}
 		if m.IOSBackup.BackupDate != nil {
 			fmt.Printf("  Backup date: %s\n", *m.IOSBackup.BackupDate)
 		}
+		if m.IOSBackup.LastBackupDate != nil {
+			fmt.Printf("  Last backup date: %s\n", *m.IOSBackup.LastBackupDate)
+		}
+		if len(m.IOSBackup.InstalledApplications) > 0 {
+			fmt.Printf("  Installed applications: %d\n", len(m.IOSBackup.InstalledApplications))
+		}
 		if m.IOSBackup.TotalFiles != nil {
 			fmt.Printf("  Total files: %d\n", *m.IOSBackup.TotalFiles)
 		}
@@ -749,34 +1377,68 @@ func saveExtractionMetadata(metadata ExtractionMetadata, manifestPath string) er
 
 // extractDeviceInfo extracts device information from backup files
 func (m *CommandMetadata) extractDeviceInfo(backupPath string) error {
-	// Try to read Info.plist first (contains most detailed info)
+	found := false
+
+	// Info.plist contains most detailed info
 	infoPlistPath := filepath.Join(backupPath, "Info.plist")
 	if info, err := readPlistInfo(infoPlistPath); err == nil {
 		m.IOSBackup.DeviceName = info.DeviceName
 		m.IOSBackup.DeviceModel = info.ProductType
 		m.IOSBackup.IOSVersion = info.ProductVersion
+		m.IOSBackup.BuildVersion = info.BuildVersion
+		m.IOSBackup.SerialNumber = info.SerialNumber
 		m.IOSBackup.BackupDate = info.Date
+		m.IOSBackup.LastBackupDate = info.LastBackupDate
 		m.IOSBackup.DeviceUUID = info.UniqueIdentifier
-		return nil
+		m.IOSBackup.InstalledApplications = info.InstalledApplications
+		found = true
 	}
 
-	// Fallback to Manifest.plist (less detailed but usually present)
+	// Manifest.plist records whether the backup is encrypted
 	manifestPlistPath := filepath.Join(backupPath, "Manifest.plist")
 	if manifest, err := readManifestPlist(manifestPlistPath); err == nil {
 		m.IOSBackup.IsEncrypted = manifest.IsEncrypted
-		return nil
+		found = true
 	}
 
-	return fmt.Errorf("could not find backup info files")
+	if !found {
+		return fmt.Errorf("could not find backup info files")
+	}
+	return nil
+}
+
+// backupDeviceName returns the device name of a backup, reading Info.plist for original backups
+// and extraction-metadata.json for extracted directories. Returns an empty string if unknown.
+func backupDeviceName(backupPath string) string {
+	if info, err := readPlistInfo(filepath.Join(backupPath, "Info.plist")); err == nil && info.DeviceName != nil {
+		return *info.DeviceName
+	}
+
+	data, err := os.ReadFile(filepath.Join(backupPath, "extraction-metadata.json"))
+	if err != nil {
+		return ""
+	}
+	var extraction ExtractionMetadata
+	if err := json.Unmarshal(data, &extraction); err != nil || extraction.CommandMetadata == nil {
+		return ""
+	}
+	if name := extraction.CommandMetadata.IOSBackup.DeviceName; name != nil {
+		return *name
+	}
+	return ""
 }
 
 // PlistInfo represents the structure of Info.plist
 type PlistInfo struct {
-	DeviceName       *string `plist:"Device Name" json:"device_name,omitempty"`
-	ProductType      *string `plist:"Product Type" json:"product_type,omitempty"`
-	ProductVersion   *string `plist:"Product Version" json:"product_version,omitempty"`
-	Date             *string `plist:"Date" json:"date,omitempty"`
-	UniqueIdentifier *string `plist:"Unique Identifier" json:"unique_identifier,omitempty"`
+	DeviceName            *string  `plist:"Device Name" json:"device_name,omitempty"`
+	ProductType           *string  `plist:"Product Type" json:"product_type,omitempty"`
+	ProductVersion        *string  `plist:"Product Version" json:"product_version,omitempty"`
+	BuildVersion          *string  `plist:"Build Version" json:"build_version,omitempty"`
+	SerialNumber          *string  `plist:"Serial Number" json:"serial_number,omitempty"`
+	Date                  *string  `plist:"Date" json:"date,omitempty"`
+	LastBackupDate        *string  `plist:"Last Backup Date" json:"last_backup_date,omitempty"`
+	UniqueIdentifier      *string  `plist:"Unique Identifier" json:"unique_identifier,omitempty"`
+	InstalledApplications []string `plist:"Installed Applications" json:"installed_applications,omitempty"`
 }
 
 // ManifestPlist represents key fields from Manifest.plist
@@ -784,84 +1446,80 @@ type ManifestPlist struct {
 	IsEncrypted bool `plist:"IsEncrypted" json:"is_encrypted"`
 }
 
-// readPlistInfo reads device info from Info.plist
+// readPlistInfo reads device info from Info.plist (XML or binary)
 func readPlistInfo(path string) (*PlistInfo, error) {
-	data, err := os.ReadFile(path)
+	dict, err := readPlistDict(path)
 	if err != nil {
 		return nil, err
 	}
 
-	// Simple plist parsing for key-value pairs
-	info := &PlistInfo{}
-	content := string(data)
-
-	// Extract device name
-	if name := extractPlistValue(content, "Device Name"); name != "" {
-		info.DeviceName = &name
-	}
-
-	// Extract product type (model)
-	if model := extractPlistValue(content, "Product Type"); model != "" {
-		info.ProductType = &model
-	}
-
-	// Extract iOS version
-	if version := extractPlistValue(content, "Product Version"); version != "" {
-		info.ProductVersion = &version
-	}
-
-	// Extract backup date
-	if date := extractPlistValue(content, "Date"); date != "" {
-		info.Date = &date
+	info := &PlistInfo{
+		DeviceName:       plistString(dict, "Device Name"),
+		ProductType:      plistString(dict, "Product Type"),
+		ProductVersion:   plistString(dict, "Product Version"),
+		BuildVersion:     plistString(dict, "Build Version"),
+		SerialNumber:     plistString(dict, "Serial Number"),
+		Date:             plistString(dict, "Date"),
+		LastBackupDate:   plistString(dict, "Last Backup Date"),
+		UniqueIdentifier: plistString(dict, "Unique Identifier"),
 	}
 
-	// Extract device UUID
-	if uuid := extractPlistValue(content, "Unique Identifier"); uuid != "" {
-		info.UniqueIdentifier = &uuid
+	if apps, ok := dict["Installed Applications"].([]any); ok {
+		for _, app := range apps {
+			if bundleID, ok := app.(string); ok {
+				info.InstalledApplications = append(info.InstalledApplications, bundleID)
+			}
+		}
 	}
 
 	return info, nil
 }
 
-// readManifestPlist reads encryption info from Manifest.plist
+// readManifestPlist reads encryption info from Manifest.plist (XML or binary)
 func readManifestPlist(path string) (*ManifestPlist, error) {
-	data, err := os.ReadFile(path)
+	dict, err := readPlistDict(path)
 	if err != nil {
 		return nil, err
 	}
 
-	manifest := &ManifestPlist{}
-	content := string(data)
-
-	// Check for encryption marker
-	manifest.IsEncrypted = strings.Contains(content, "<key>IsEncrypted</key>") &&
-		strings.Contains(content, "<true/>")
-
-	return manifest, nil
+	encrypted, _ := dict["IsEncrypted"].(bool)
+	return &ManifestPlist{IsEncrypted: encrypted}, nil
 }
 
-// extractPlistValue extracts a string value from plist content
-func extractPlistValue(content, key string) string {
-	keyPattern := fmt.Sprintf("<key>%s</key>", key)
-	keyIndex := strings.Index(content, keyPattern)
-	if keyIndex == -1 {
-		return ""
+// readPlistDict decodes a property list file whose root is a dictionary
+func readPlistDict(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
 
-	// Look for the next <string> tag after the key
-	searchStart := keyIndex + len(keyPattern)
-	stringStart := strings.Index(content[searchStart:], "<string>")
-	if stringStart == -1 {
-		return ""
+	decoded, err := plist.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
 	}
-	stringStart += searchStart + 8 // Length of "<string>"
-
-	stringEnd := strings.Index(content[stringStart:], "</string>")
-	if stringEnd == -1 {
-		return ""
+	dict, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a dictionary", filepath.Base(path))
 	}
+	return dict, nil
+}
 
-	return content[stringStart : stringStart+stringEnd]
+// plistString returns a plist value as a string, formatting dates as RFC 3339.
+// Returns nil when the key is missing or empty.
+func plistString(dict map[string]any, key string) *string {
+	var value string
+	switch v := dict[key].(type) {
+	case string:
+		value = v
+	case time.Time:
+		value = v.UTC().Format(time.RFC3339)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	}
+	if value == "" {
+		return nil
+	}
+	return &value
 }
 
 // getOSVersion returns the operating system version
@@ -880,11 +1538,10 @@ func getOSVersion() string {
 
 // getMacOSVersion returns macOS version
 func getMacOSVersion() string {
-	// Try to read from system_profiler
-	if data, err := os.ReadFile("/System/Library/CoreServices/SystemVersion.plist"); err == nil {
-		content := string(data)
-		if version := extractPlistValue(content, "ProductVersion"); version != "" {
-			return fmt.Sprintf("macOS %s", version)
+	// Read the version from the system version plist
+	if dict, err := readPlistDict("/System/Library/CoreServices/SystemVersion.plist"); err == nil {
+		if version := plistString(dict, "ProductVersion"); version != nil {
+			return fmt.Sprintf("macOS %s", *version)
 		}
 	}
 	return "macOS"
@@ -914,7 +1571,7 @@ func getLinuxVersion() string {
 }
 
 // getAssetsFromBackup extracts assets from backup for metadata counting
-func getAssetsFromBackup(backupPath string) []*types.Asset {
+func getAssetsFromBackup(backupPath, password string) []*types.Asset {
 	// Create backup parser with minimal logging
 	loggerConfig := logger.Config{
 		Level:   logger.LevelInfo,
@@ -922,7 +1579,7 @@ func getAssetsFromBackup(backupPath string) []*types.Asset {
 	}
 	log := logger.New(loggerConfig)
 
-	parser, err := backup.CreateBackupParser(backupPath, log)
+	parser, err := backup.CreateBackupParserWithPassword(backupPath, password, log)
 	if err != nil {
 		return nil
 	}
@@ -937,6 +1594,37 @@ func getAssetsFromBackup(backupPath string) []*types.Asset {
 	return assets
 }
 
+// backupPasswordEnv is the environment variable holding the password of an encrypted backup
+const backupPasswordEnv = "GH_PHOTOS_BACKUP_PASSWORD"
+
+// resolveBackupPassword reads the encrypted backup password from passwordFile,
+// falling back to the GH_PHOTOS_BACKUP_PASSWORD environment variable
+func resolveBackupPassword(passwordFile string) (string, error) {
+	if passwordFile == "" {
+		return os.Getenv(backupPasswordEnv), nil
+	}
+
+	data, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+
+	// Only the line terminator is stripped; other whitespace may be part of the password
+	password := strings.TrimRight(string(data), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("password file %s is empty", passwordFile)
+	}
+	return password, nil
+}
+
+// withPasswordHint explains how to supply a password when an encrypted backup was opened without one
+func withPasswordHint(err error) error {
+	if errors.Is(err, backup.ErrPasswordRequired) {
+		return fmt.Errorf("%w (use --password-file or set %s)", err, backupPasswordEnv)
+	}
+	return err
+}
+
 // formatBytes formats bytes in human readable format
 func formatBytes(bytes int64) string {
 	const unit = 1024
@@ -975,10 +1663,15 @@ func runSync(ctx context.Context, config uploader.Config) error {
 		config.BackupPath = abs
 	}
 
+	// Device name is only needed for the {device} path template token
+	if config.DeviceName == "" && strings.Contains(config.PathTemplate, "{device}") {
+		config.DeviceName = backupDeviceName(config.BackupPath)
+	}
+
 	// Create uploader
 	ul, err := uploader.CreateUploader(config)
 	if err != nil {
-		return fmt.Errorf("failed to create uploader: %w", err)
+		return fmt.Errorf("failed to create uploader: %w", withPasswordHint(err))
 	}
 	defer ul.Close()
 
@@ -994,7 +1687,7 @@ func runSync(ctx context.Context, config uploader.Config) error {
 	}
 
 	// Get asset counts from the filtered assets (via uploader)
-	if assets := getAssetsFromBackup(config.BackupPath); assets != nil {
+	if assets := getAssetsFromBackup(config.BackupPath, config.Password); assets != nil {
 		metadata.setAssetCounts(assets)
 	}
 
@@ -1164,12 +1857,270 @@ func validateTraditionalSearch(backupPath string) error {
 }
 
 // runList lists assets in a backup
-func runList(backupPath string) error {
-	fmt.Printf("Listing assets in backup: %s\n", backupPath)
+func runList(config uploader.Config, opts listOptions) error {
+	// Parser progress goes to stderr so JSON/CSV output on stdout stays machine-readable
+	log := logger.New(logger.Config{Level: logger.LogLevel(config.LogLevel), Output: os.Stderr})
 
-	// This would use the backup parser to list assets
-	// For now, just validate the backup exists
-	return runValidate(backupPath)
+	parser, err := backup.CreateBackupParserWithPassword(config.BackupPath, config.Password, log)
+	if err != nil {
+		return fmt.Errorf("failed to create backup parser: %w", withPasswordHint(err))
+	}
+	defer parser.Close()
+
+	assets, err := parser.ParseAssets()
+	if err != nil {
+		return fmt.Errorf("failed to parse assets: %w", err)
+	}
+
+	// Device name is only needed for the {device} path template token
+	if strings.Contains(config.PathTemplate, "{device}") {
+		config.DeviceName = backupDeviceName(config.BackupPath)
+	}
+
+	filtered := uploader.FilterAssets(config, assets, log)
+	return writeAssetList(os.Stdout, filtered, opts)
+}
+
+// listOptions controls how the list command prints assets
+type listOptions struct {
+	Format  string // table, json, or csv
+	SortBy  string // date or type
+	GroupBy string // "", date, or type
+}
+
+// listEntry is a single asset as printed by the list command
+type listEntry struct {
+	ID           string    `json:"id"`
+	Filename     string    `json:"filename"`
+	Type         string    `json:"type"`
+	CreationDate time.Time `json:"creation_date"`
+	Size         int64     `json:"size"`
+	Flags        []string  `json:"flags"`
+	TargetPath   string    `json:"target_path"`
+}
+
+// listGroup is a group of assets as printed by the list command with --group-by
+type listGroup struct {
+	Group  string      `json:"group"`
+	Assets []listEntry `json:"assets"`
+}
+
+// configureListCommand handles flag parsing and validation for the list command
+func configureListCommand(config *uploader.Config, opts *listOptions, cmd *cobra.Command, startDateStr, endDateStr string) error {
+	if err := parseDateFilters(config, startDateStr, endDateStr); err != nil {
+		return err
+	}
+
+	// Only warnings are logged by default so the listing isn't interleaved with progress messages
+	if err := configureLogLevel(config, cmd); err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("log-level") && os.Getenv("LOG_LEVEL") == "" {
+		config.LogLevel = "warn"
+	}
+
+	if err := validatePathGranularity(config); err != nil {
+		return err
+	}
+	if err := validateTimezone(config); err != nil {
+		return err
+	}
+	if err := validateNear(config); err != nil {
+		return err
+	}
+	if err := validateGazetteer(config); err != nil {
+		return err
+	}
+	if err := validateBurstMode(config); err != nil {
+		return err
+	}
+
+	return validateListOptions(opts)
+}
+
+// validateListOptions normalizes and validates the list output options
+func validateListOptions(opts *listOptions) error {
+	format, ok := utils.ValidateStringInSet(opts.Format, map[string]bool{"table": true, "json": true, "csv": true})
+	if !ok {
+		return fmt.Errorf("invalid format '%s'. Valid values: table, json, csv", opts.Format)
+	}
+	opts.Format = format
+
+	sortBy, ok := utils.ValidateStringInSet(opts.SortBy, map[string]bool{"date": true, "type": true})
+	if !ok {
+		return fmt.Errorf("invalid sort '%s'. Valid values: date, type", opts.SortBy)
+	}
+	opts.SortBy = sortBy
+
+	groupBy, ok := utils.ValidateStringInSet(opts.GroupBy, map[string]bool{"": true, "date": true, "type": true})
+	if !ok {
+		return fmt.Errorf("invalid group-by '%s'. Valid values: date, type", opts.GroupBy)
+	}
+	opts.GroupBy = groupBy
+
+	return nil
+}
+
+// listGroupKey returns the group an asset belongs to for --group-by (empty when not grouping)
+func listGroupKey(asset *types.Asset, groupBy string) string {
+	switch groupBy {
+	case "date":
+		return asset.CreationDate.Format("2006-01-02")
+	case "type":
+		return string(asset.Type)
+	default:
+		return ""
+	}
+}
+
+// sortAssetsForList orders assets by group first (so groups are contiguous) and then by the sort option
+func sortAssetsForList(assets []*types.Asset, opts listOptions) {
+	sort.SliceStable(assets, func(i, j int) bool {
+		a, b := assets[i], assets[j]
+		if ga, gb := listGroupKey(a, opts.GroupBy), listGroupKey(b, opts.GroupBy); ga != gb {
+			return ga < gb
+		}
+		if opts.SortBy == "type" && a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if !a.CreationDate.Equal(b.CreationDate) {
+			return a.CreationDate.Before(b.CreationDate)
+		}
+		return a.Filename < b.Filename
+	})
+}
+
+// assetFlagNames returns the names of the flags set on an asset
+func assetFlagNames(asset *types.Asset) []string {
+	flags := []string{}
+	if asset.Flags.Hidden {
+		flags = append(flags, "hidden")
+	}
+	if asset.Flags.RecentlyDeleted {
+		flags = append(flags, "recently_deleted")
+	}
+	if asset.Flags.Screenshot {
+		flags = append(flags, "screenshot")
+	}
+	if asset.Flags.Burst {
+		flags = append(flags, "burst")
+	}
+	if asset.Flags.LivePhoto {
+		flags = append(flags, "live_photo")
+	}
+	if asset.Flags.Edited {
+		flags = append(flags, "edited")
+	}
+	if asset.Flags.Favorite {
+		flags = append(flags, "favorite")
+	}
+	return flags
+}
+
+// groupAssetsForList sorts the assets and splits them into groups (a single unnamed group when not grouping)
+func groupAssetsForList(assets []*types.Asset, opts listOptions) []listGroup {
+	sorted := append([]*types.Asset{}, assets...)
+	sortAssetsForList(sorted, opts)
+
+	var groups []listGroup
+	for _, asset := range sorted {
+		key := listGroupKey(asset, opts.GroupBy)
+		if len(groups) == 0 || groups[len(groups)-1].Group != key {
+			groups = append(groups, listGroup{Group: key, Assets: []listEntry{}})
+		}
+		current := &groups[len(groups)-1]
+		current.Assets = append(current.Assets, listEntry{
+			ID:           asset.ID,
+			Filename:     asset.Filename,
+			Type:         string(asset.Type),
+			CreationDate: asset.CreationDate,
+			Size:         asset.FileSize,
+			Flags:        assetFlagNames(asset),
+			TargetPath:   asset.TargetPath,
+		})
+	}
+	return groups
+}
+
+// writeAssetList prints assets in the requested format
+func writeAssetList(w io.Writer, assets []*types.Asset, opts listOptions) error {
+	groups := groupAssetsForList(assets, opts)
+
+	switch opts.Format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if opts.GroupBy != "" {
+			if groups == nil {
+				groups = []listGroup{}
+			}
+			return encoder.Encode(groups)
+		}
+		entries := []listEntry{}
+		for _, group := range groups {
+			entries = append(entries, group.Assets...)
+		}
+		return encoder.Encode(entries)
+
+	case "csv":
+		writer := csv.NewWriter(w)
+		header := []string{"id", "filename", "type", "creation_date", "size_bytes", "flags", "target_path"}
+		if opts.GroupBy != "" {
+			header = append([]string{"group"}, header...)
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for _, group := range groups {
+			for _, entry := range group.Assets {
+				record := []string{
+					entry.ID,
+					entry.Filename,
+					entry.Type,
+					entry.CreationDate.Format(time.RFC3339),
+					strconv.FormatInt(entry.Size, 10),
+					strings.Join(entry.Flags, ";"),
+					entry.TargetPath,
+				}
+				if opts.GroupBy != "" {
+					record = append([]string{group.Group}, record...)
+				}
+				if err := writer.Write(record); err != nil {
+					return err
+				}
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	default: // table
+		var totalAssets int
+		var totalSize int64
+		for _, group := range groups {
+			if opts.GroupBy != "" {
+				fmt.Fprintf(w, "\n%s (%d assets)\n", group.Group, len(group.Assets))
+			}
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tFILENAME\tTYPE\tCREATED\tSIZE\tFLAGS\tTARGET PATH")
+			for _, entry := range group.Assets {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					entry.ID,
+					entry.Filename,
+					entry.Type,
+					entry.CreationDate.Format("2006-01-02 15:04:05 -07:00"),
+					formatBytes(entry.Size),
+					strings.Join(entry.Flags, ","),
+					entry.TargetPath)
+				totalAssets++
+				totalSize += entry.Size
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(w, "\nTotal: %d assets (%s)\n", totalAssets, formatBytes(totalSize))
+		return nil
+	}
 }
 
 // loadLastCommandConfig loads configuration from the last successful run
@@ -1217,6 +2168,51 @@ func loadLastCommandConfig(config *uploader.Config, cmd *cobra.Command, args []s
 	if !cmd.Flags().Changed("ignore") && len(trail.Metadata.Invocation.Flags.IgnorePatterns) > 0 {
 		config.IgnorePatterns = trail.Metadata.Invocation.Flags.IgnorePatterns
 	}
+	if !cmd.Flags().Changed("albums") && len(trail.Metadata.Invocation.Flags.Albums) > 0 {
+		config.Albums = trail.Metadata.Invocation.Flags.Albums
+	}
+	if !cmd.Flags().Changed("person") && len(trail.Metadata.Invocation.Flags.People) > 0 {
+		config.People = trail.Metadata.Invocation.Flags.People
+	}
+	if !cmd.Flags().Changed("organize-by-album") {
+		config.OrganizeByAlbum = trail.Metadata.Invocation.Flags.OrganizeByAlbum
+	}
+	if !cmd.Flags().Changed("live-photo-mode") && trail.Metadata.Invocation.Flags.LivePhotoMode != "" {
+		config.LivePhotoMode = trail.Metadata.Invocation.Flags.LivePhotoMode
+	}
+	if !cmd.Flags().Changed("burst-mode") && trail.Metadata.Invocation.Flags.BurstMode != "" {
+		config.BurstMode = trail.Metadata.Invocation.Flags.BurstMode
+	}
+	if !cmd.Flags().Changed("edits") && trail.Metadata.Invocation.Flags.EditsMode != "" {
+		config.EditsMode = trail.Metadata.Invocation.Flags.EditsMode
+	}
+	if !cmd.Flags().Changed("timezone") && trail.Metadata.Invocation.Flags.Timezone != "" {
+		config.Timezone = trail.Metadata.Invocation.Flags.Timezone
+	}
+	if !cmd.Flags().Changed("filename-template") && trail.Metadata.Invocation.Flags.FilenameTemplate != "" {
+		config.FilenameTemplate = trail.Metadata.Invocation.Flags.FilenameTemplate
+	}
+	if !cmd.Flags().Changed("path-template") && trail.Metadata.Invocation.Flags.PathTemplate != "" {
+		config.PathTemplate = trail.Metadata.Invocation.Flags.PathTemplate
+	}
+	if !cmd.Flags().Changed("remote-pre-scan") {
+		config.RemotePreScan = trail.Metadata.Invocation.Flags.RemotePreScan
+	}
+	if !cmd.Flags().Changed("asset-list") && trail.Metadata.Invocation.Flags.AssetList != "" {
+		config.AssetList = trail.Metadata.Invocation.Flags.AssetList
+	}
+	if !cmd.Flags().Changed("near") && trail.Metadata.Invocation.Flags.Near != "" {
+		config.Near = trail.Metadata.Invocation.Flags.Near
+	}
+	if !cmd.Flags().Changed("gazetteer") && trail.Metadata.Invocation.Flags.Gazetteer != "" {
+		config.Gazetteer = trail.Metadata.Invocation.Flags.Gazetteer
+	}
+	if !cmd.Flags().Changed("favorites-only") {
+		config.FavoritesOnly = trail.Metadata.Invocation.Flags.FavoritesOnly
+	}
+	if !cmd.Flags().Changed("favorites-remote") && trail.Metadata.Invocation.Flags.FavoritesRemote != "" {
+		config.FavoritesRemote = trail.Metadata.Invocation.Flags.FavoritesRemote
+	}
 
 	// Override backup path and remote if not provided as arguments
 	if len(args) == 0 {
@@ -1290,6 +2286,51 @@ func buildSyncCommand(invocation audit.Invocation, sourcePath string) string {
 	if flags.PathGranularity != "" && flags.PathGranularity != "day" {
 		parts = append(parts, fmt.Sprintf("--path-granularity=%s", flags.PathGranularity))
 	}
+	if len(flags.Albums) > 0 {
+		parts = append(parts, fmt.Sprintf("--albums=%s", strings.Join(flags.Albums, ",")))
+	}
+	for _, person := range flags.People {
+		parts = append(parts, fmt.Sprintf("--person='%s'", person))
+	}
+	if flags.OrganizeByAlbum {
+		parts = append(parts, "--organize-by-album")
+	}
+	if flags.LivePhotoMode != "" && flags.LivePhotoMode != string(types.LivePhotoModeBoth) {
+		parts = append(parts, fmt.Sprintf("--live-photo-mode=%s", flags.LivePhotoMode))
+	}
+	if flags.BurstMode != "" && flags.BurstMode != string(types.BurstModeAll) {
+		parts = append(parts, fmt.Sprintf("--burst-mode=%s", flags.BurstMode))
+	}
+	if flags.EditsMode != "" && flags.EditsMode != string(types.EditsModeOriginal) {
+		parts = append(parts, fmt.Sprintf("--edits=%s", flags.EditsMode))
+	}
+	if flags.Timezone != "" {
+		parts = append(parts, fmt.Sprintf("--timezone=%s", flags.Timezone))
+	}
+	if flags.FilenameTemplate != "" {
+		parts = append(parts, fmt.Sprintf("--filename-template='%s'", flags.FilenameTemplate))
+	}
+	if flags.PathTemplate != "" {
+		parts = append(parts, fmt.Sprintf("--path-template='%s'", flags.PathTemplate))
+	}
+	if flags.RemotePreScan {
+		parts = append(parts, "--remote-pre-scan")
+	}
+	if flags.AssetList != "" {
+		parts = append(parts, fmt.Sprintf("--asset-list=%s", flags.AssetList))
+	}
+	if flags.Near != "" {
+		parts = append(parts, fmt.Sprintf("--near=%s", flags.Near))
+	}
+	if flags.Gazetteer != "" {
+		parts = append(parts, fmt.Sprintf("--gazetteer=%s", flags.Gazetteer))
+	}
+	if flags.FavoritesOnly {
+		parts = append(parts, "--favorites-only")
+	}
+	if flags.FavoritesRemote != "" {
+		parts = append(parts, fmt.Sprintf("--favorites-remote=%s", flags.FavoritesRemote))
+	}
 
 	return strings.Join(parts, " ")
 }
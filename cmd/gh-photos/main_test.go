@@ -3,6 +3,7 @@ package main
 import (
 	"testing"
 
+	"github.com/grantbirki/gh-photos/internal/uploader"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -60,3 +61,15 @@ func TestRootCommandLogLevel(t *testing.T) {
 	assert.NotNil(t, logLevelFlag)
 	assert.Equal(t, "info", logLevelFlag.DefValue, "log-level should default to info")
 }
+
+func TestValidatePathGranularityWithPathTemplate(t *testing.T) {
+	config := &uploader.Config{PathGranularity: "Month", PathTemplate: "{year}/{year}-{month} {monthname}/{filename}"}
+	assert.NoError(t, validatePathGranularity(config))
+	assert.Equal(t, "month", config.PathGranularity)
+
+	config = &uploader.Config{PathTemplate: "{year}/{week}"}
+	assert.Error(t, validatePathGranularity(config))
+
+	config = &uploader.Config{PathTemplate: "{year}/{filename}", OrganizeByAlbum: true}
+	assert.Error(t, validatePathGranularity(config))
+}
@@ -45,19 +45,24 @@ func TestSetAssetCounts(t *testing.T) {
 		{Type: types.AssetTypeScreenshot},
 		{Type: types.AssetTypeLivePhoto},
 		{Type: types.AssetTypeBurst},
+		{Type: types.AssetTypePortrait},
+		{Type: types.AssetTypeSlomo},
 	}
 
 	metadata.setAssetCounts(assets)
 
-	// Check counts
-	if metadata.AssetCounts.Total != 6 {
-		t.Errorf("Expected total count 6, got %d", metadata.AssetCounts.Total)
+	// Check counts (subtypes are counted with their photo or video base type as well)
+	if metadata.AssetCounts.Total != 8 {
+		t.Errorf("Expected total count 8, got %d", metadata.AssetCounts.Total)
 	}
-	if metadata.AssetCounts.Photos != 2 {
-		t.Errorf("Expected photos count 2, got %d", metadata.AssetCounts.Photos)
+	if metadata.AssetCounts.Photos != 3 {
+		t.Errorf("Expected photos count 3, got %d", metadata.AssetCounts.Photos)
 	}
-	if metadata.AssetCounts.Videos != 1 {
-		t.Errorf("Expected videos count 1, got %d", metadata.AssetCounts.Videos)
+	if metadata.AssetCounts.Videos != 2 {
+		t.Errorf("Expected videos count 2, got %d", metadata.AssetCounts.Videos)
+	}
+	if metadata.AssetCounts.Subtypes["portrait"] != 1 || metadata.AssetCounts.Subtypes["slomo"] != 1 {
+		t.Errorf("Expected one portrait and one slomo subtype, got %v", metadata.AssetCounts.Subtypes)
 	}
 	if metadata.AssetCounts.Screenshots != 1 {
 		t.Errorf("Expected screenshots count 1, got %d", metadata.AssetCounts.Screenshots)
@@ -183,29 +188,114 @@ func TestSaveToManifest(t *testing.T) {
 	}
 }
 
-func TestExtractPlistValue(t *testing.T) {
+func TestReadPlistInfo(t *testing.T) {
+	tempDir := t.TempDir()
 	content := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
 <dict>
+	<key>Build Version</key>
+	<string>21G80</string>
 	<key>Device Name</key>
 	<string>Test iPhone</string>
+	<key>Installed Applications</key>
+	<array>
+		<string>com.example.camera</string>
+		<string>com.example.notes</string>
+	</array>
+	<key>Last Backup Date</key>
+	<date>2025-09-18T12:30:00Z</date>
 	<key>Product Version</key>
 	<string>17.6</string>
-</dict>`
+	<key>Serial Number</key>
+	<string>F2LXK0ABCDEF</string>
+</dict>
+</plist>`
+	path := filepath.Join(tempDir, "Info.plist")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write Info.plist: %v", err)
+	}
+
+	info, err := readPlistInfo(path)
+	if err != nil {
+		t.Fatalf("Failed to read Info.plist: %v", err)
+	}
+
+	if info.DeviceName == nil || *info.DeviceName != "Test iPhone" {
+		t.Errorf("Expected 'Test iPhone', got %v", info.DeviceName)
+	}
+	if info.ProductVersion == nil || *info.ProductVersion != "17.6" {
+		t.Errorf("Expected '17.6', got %v", info.ProductVersion)
+	}
+	if info.BuildVersion == nil || *info.BuildVersion != "21G80" {
+		t.Errorf("Expected '21G80', got %v", info.BuildVersion)
+	}
+	if info.SerialNumber == nil || *info.SerialNumber != "F2LXK0ABCDEF" {
+		t.Errorf("Expected 'F2LXK0ABCDEF', got %v", info.SerialNumber)
+	}
+	if info.LastBackupDate == nil || *info.LastBackupDate != "2025-09-18T12:30:00Z" {
+		t.Errorf("Expected '2025-09-18T12:30:00Z', got %v", info.LastBackupDate)
+	}
+	if len(info.InstalledApplications) != 2 || info.InstalledApplications[0] != "com.example.camera" {
+		t.Errorf("Expected 2 installed applications, got %v", info.InstalledApplications)
+	}
+
+	// Missing keys stay nil
+	if info.ProductType != nil {
+		t.Errorf("Expected nil product type, got %v", *info.ProductType)
+	}
+}
+
+func TestReadManifestPlist(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Another key set to true must not be mistaken for IsEncrypted
+	xmlPath := filepath.Join(tempDir, "xml.plist")
+	xmlContent := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>IsEncrypted</key>
+	<false/>
+	<key>WasPasscodeSet</key>
+	<true/>
+</dict>
+</plist>`
+	if err := os.WriteFile(xmlPath, []byte(xmlContent), 0644); err != nil {
+		t.Fatalf("Failed to write plist: %v", err)
+	}
 
-	deviceName := extractPlistValue(content, "Device Name")
-	if deviceName != "Test iPhone" {
-		t.Errorf("Expected 'Test iPhone', got '%s'", deviceName)
+	manifest, err := readManifestPlist(xmlPath)
+	if err != nil {
+		t.Fatalf("Failed to read XML Manifest.plist: %v", err)
 	}
+	if manifest.IsEncrypted {
+		t.Error("Expected XML Manifest.plist to be unencrypted")
+	}
+
+	// Binary plist as written by Finder: {"IsEncrypted": true}
+	binaryContent := []byte("bplist00")
+	binaryContent = append(binaryContent, 0xD1, 0x01, 0x02) // dict with one entry
+	binaryContent = append(binaryContent, 0x5B)             // 11 byte ASCII string
+	binaryContent = append(binaryContent, "IsEncrypted"...)
+	binaryContent = append(binaryContent, 0x09)      // true
+	binaryContent = append(binaryContent, 8, 11, 23) // offset table
+	trailer := make([]byte, 32)
+	trailer[6], trailer[7] = 1, 1
+	trailer[15] = 3  // object count
+	trailer[31] = 24 // offset table offset
+	binaryContent = append(binaryContent, trailer...)
 
-	version := extractPlistValue(content, "Product Version")
-	if version != "17.6" {
-		t.Errorf("Expected '17.6', got '%s'", version)
+	binaryPath := filepath.Join(tempDir, "binary.plist")
+	if err := os.WriteFile(binaryPath, binaryContent, 0644); err != nil {
+		t.Fatalf("Failed to write plist: %v", err)
 	}
 
-	// Test non-existent key
-	missing := extractPlistValue(content, "Missing Key")
-	if missing != "" {
-		t.Errorf("Expected empty string for missing key, got '%s'", missing)
+	manifest, err = readManifestPlist(binaryPath)
+	if err != nil {
+		t.Fatalf("Failed to read binary Manifest.plist: %v", err)
+	}
+	if !manifest.IsEncrypted {
+		t.Error("Expected binary Manifest.plist to be encrypted")
 	}
 }
 
@@ -72,6 +72,40 @@ func TestBuildSyncCommand(t *testing.T) {
 			sourcePath: "/path/to/backup",
 			expected:   "sync /path/to/backup dropbox:Photos --include-hidden --include-recently-deleted --parallel=2 --skip-existing --dry-run --log-level=debug --types=photo --verify --checksum",
 		},
+		{
+			name: "sync command with favorites",
+			invocation: audit.Invocation{
+				Remote: "gdrive:Photos",
+				Flags: audit.InvocationFlags{
+					FavoritesOnly:   true,
+					FavoritesRemote: "family:Shared",
+				},
+			},
+			sourcePath: "/path/to/extracted",
+			expected:   "sync /path/to/extracted gdrive:Photos --favorites-only --favorites-remote=family:Shared",
+		},
+		{
+			name: "sync command with burst mode",
+			invocation: audit.Invocation{
+				Remote: "gdrive:Photos",
+				Flags: audit.InvocationFlags{
+					BurstMode: "picked",
+				},
+			},
+			sourcePath: "/path/to/extracted",
+			expected:   "sync /path/to/extracted gdrive:Photos --burst-mode=picked",
+		},
+		{
+			name: "sync command with default burst mode (should not include)",
+			invocation: audit.Invocation{
+				Remote: "gdrive:Photos",
+				Flags: audit.InvocationFlags{
+					BurstMode: "all",
+				},
+			},
+			sourcePath: "/path/to/extracted",
+			expected:   "sync /path/to/extracted gdrive:Photos",
+		},
 		{
 			name: "sync command with default parallel (should not include)",
 			invocation: audit.Invocation{
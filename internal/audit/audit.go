@@ -57,6 +57,21 @@ type InvocationFlags struct {
 	Checksum               bool       `json:"checksum,omitempty"`
 	IgnorePatterns         []string   `json:"ignore_patterns,omitempty"`
 	PathGranularity        string     `json:"path_granularity,omitempty"`
+	Albums                 []string   `json:"albums,omitempty"`
+	People                 []string   `json:"people,omitempty"`
+	OrganizeByAlbum        bool       `json:"organize_by_album,omitempty"`
+	LivePhotoMode          string     `json:"live_photo_mode,omitempty"`
+	BurstMode              string     `json:"burst_mode,omitempty"`
+	EditsMode              string     `json:"edits_mode,omitempty"`
+	Timezone               string     `json:"timezone,omitempty"`
+	FilenameTemplate       string     `json:"filename_template,omitempty"`
+	PathTemplate           string     `json:"path_template,omitempty"`
+	RemotePreScan          bool       `json:"remote_pre_scan,omitempty"`
+	AssetList              string     `json:"asset_list,omitempty"`
+	Near                   string     `json:"near,omitempty"`
+	Gazetteer              string     `json:"gazetteer,omitempty"`
+	FavoritesOnly          bool       `json:"favorites_only,omitempty"`
+	FavoritesRemote        string     `json:"favorites_remote,omitempty"`
 }
 
 // Summary provides aggregate statistics about the operation
@@ -78,16 +93,37 @@ type SystemInfo struct {
 
 // AssetEntry represents a single asset record in the audit trail
 type AssetEntry struct {
-	UUID       string    `json:"uuid"`
+	UUID       string    `json:"uuid"`                       // stable asset identity (Photos UUID, or Z_PK when the schema has none)
+	CloudGUID  string    `json:"cloud_asset_guid,omitempty"` // iCloud Photos asset GUID
+	PhotosID   string    `json:"photos_id,omitempty"`        // Photos database primary key (Z_PK), for debugging
 	LocalPath  string    `json:"local_path"`
 	RemotePath string    `json:"remote_path"`
 	SizeBytes  int64     `json:"size_bytes"`
 	SHA256     string    `json:"sha256,omitempty"`
 	Type       string    `json:"type"`
+	Subtype    string    `json:"subtype,omitempty"` // photo or video subtype, e.g. "portrait" or "slomo"
 	Hidden     bool      `json:"hidden"`
 	Deleted    bool      `json:"deleted"`
+	Favorite   bool      `json:"favorite"`
 	CreatedAt  time.Time `json:"created_at"`
-	Status     string    `json:"status"` // uploaded, skipped, failed
+	Albums     []string  `json:"albums,omitempty"`
+	People     []string  `json:"people,omitempty"`
+	Country    string    `json:"country,omitempty"`
+	City       string    `json:"city,omitempty"`
+	// Live Photo pairing: role of this file ("still" or "video") and the remote path of its counterpart
+	LivePhotoRole    string `json:"live_photo_role,omitempty"`
+	PairedRemotePath string `json:"paired_remote_path,omitempty"`
+	Edited           bool   `json:"edited,omitempty"` // entry is the edited render of the asset
+	Status           string `json:"status"`           // uploaded, skipped, failed
+}
+
+// LivePhotoComponent describes one file of a Live Photo pair
+type LivePhotoComponent struct {
+	Role             string // "still" or "video"
+	LocalPath        string
+	RemotePath       string
+	PairedRemotePath string
+	SizeBytes        int64
 }
 
 // TrailManager manages audit trail creation and persistence
@@ -152,23 +188,58 @@ func (tm *TrailManager) SetInvocation(remote string, flags InvocationFlags) {
 // AddAsset adds an asset entry to the audit trail
 func (tm *TrailManager) AddAsset(asset *types.Asset, remotePath, status string) {
 	entry := AssetEntry{
-		UUID:       asset.ID,
+		UUID:       asset.StableID(),
+		CloudGUID:  asset.CloudAssetGUID,
+		PhotosID:   asset.ID,
 		LocalPath:  asset.SourcePath,
 		RemotePath: remotePath,
 		SizeBytes:  asset.FileSize,
 		SHA256:     asset.Checksum,
 		Type:       tm.convertAssetTypeToAuditFormat(asset.Type),
+		Subtype:    asset.Type.Subtype(),
 		Hidden:     asset.Flags.Hidden,
 		Deleted:    asset.Flags.RecentlyDeleted,
+		Favorite:   asset.Flags.Favorite,
 		CreatedAt:  asset.CreationDate,
+		Albums:     asset.Albums,
+		People:     asset.People,
+		Country:    asset.Country,
+		City:       asset.City,
 		Status:     status,
 	}
 	tm.trail.Assets = append(tm.trail.Assets, entry)
 }
 
-// convertAssetTypeToAuditFormat converts AssetType to audit trail format (singular)
+// AddLivePhotoComponent adds one half of a Live Photo (the still or its paired video) to the audit trail
+func (tm *TrailManager) AddLivePhotoComponent(asset *types.Asset, component LivePhotoComponent, status string) {
+	tm.AddAsset(asset, component.RemotePath, status)
+
+	entry := &tm.trail.Assets[len(tm.trail.Assets)-1]
+	entry.LocalPath = component.LocalPath
+	entry.SizeBytes = component.SizeBytes
+	entry.LivePhotoRole = component.Role
+	entry.PairedRemotePath = component.PairedRemotePath
+	if component.Role == types.LivePhotoRoleVideo {
+		entry.SHA256 = "" // checksum belongs to the still
+	}
+}
+
+// AddEditedRender adds the edited render of an asset to the audit trail
+func (tm *TrailManager) AddEditedRender(asset *types.Asset, remotePath string, status string) {
+	tm.AddAsset(asset, remotePath, status)
+
+	entry := &tm.trail.Assets[len(tm.trail.Assets)-1]
+	entry.LocalPath = asset.EditedPath
+	entry.SizeBytes = asset.EditedSize
+	entry.SHA256 = "" // checksum belongs to the original
+	entry.Edited = true
+}
+
+// convertAssetTypeToAuditFormat converts AssetType to audit trail format (singular).
+// Photo and video subtypes are recorded as "photo" and "video" so existing consumers keep working;
+// the subtype itself is recorded separately (see AssetType.Subtype).
 func (tm *TrailManager) convertAssetTypeToAuditFormat(assetType types.AssetType) string {
-	switch assetType {
+	switch assetType.Base() {
 	case types.AssetTypePhoto:
 		return "photo"
 	case types.AssetTypeVideo:
@@ -110,6 +110,7 @@ func TestAddAsset(t *testing.T) {
 		Flags: types.AssetFlags{
 			Hidden:          false,
 			RecentlyDeleted: false,
+			Favorite:        true,
 		},
 	}
 
@@ -142,6 +143,61 @@ func TestAddAsset(t *testing.T) {
 	if entry.Type != "photo" {
 		t.Errorf("Expected type 'photo', got '%s'", entry.Type)
 	}
+
+	if !entry.Favorite {
+		t.Errorf("Expected the favorite flag to be recorded")
+	}
+}
+
+func TestAddAssetSubtype(t *testing.T) {
+	tm, err := CreateTrailManager("test-version")
+	if err != nil {
+		t.Fatalf("Failed to create trail manager: %v", err)
+	}
+
+	tm.AddAsset(&types.Asset{ID: "1", Filename: "IMG_001.HEIC", Type: types.AssetTypePortrait}, "portrait/IMG_001.HEIC", "uploaded")
+	tm.AddAsset(&types.Asset{ID: "2", Filename: "IMG_002.MOV", Type: types.AssetTypeSlomo}, "slomo/IMG_002.MOV", "uploaded")
+	tm.AddAsset(&types.Asset{ID: "3", Filename: "IMG_003.MOV", Type: types.AssetTypeVideo}, "videos/IMG_003.MOV", "uploaded")
+
+	expected := []struct{ assetType, subtype string }{
+		{"photo", "portrait"},
+		{"video", "slomo"},
+		{"video", ""},
+	}
+	for i, want := range expected {
+		entry := tm.trail.Assets[i]
+		if entry.Type != want.assetType || entry.Subtype != want.subtype {
+			t.Errorf("Entry %d: expected type '%s' and subtype '%s', got '%s' and '%s'", i, want.assetType, want.subtype, entry.Type, entry.Subtype)
+		}
+	}
+}
+
+func TestAddAssetKeyedByPhotosUUID(t *testing.T) {
+	tm, err := CreateTrailManager("test-version")
+	if err != nil {
+		t.Fatalf("Failed to create trail manager: %v", err)
+	}
+
+	asset := &types.Asset{
+		ID:             "42",
+		UUID:           "5A1F2C3D-0000-4000-8000-00000000002A",
+		CloudAssetGUID: "AZxQ3f1cE9Lr",
+		SourcePath:     "/test/source/IMG_042.HEIC",
+		Filename:       "IMG_042.HEIC",
+		Type:           types.AssetTypePhoto,
+	}
+	tm.AddAsset(asset, "photos/IMG_042.HEIC", "uploaded")
+
+	entry := tm.trail.Assets[0]
+	if entry.UUID != asset.UUID {
+		t.Errorf("Expected UUID '%s', got '%s'", asset.UUID, entry.UUID)
+	}
+	if entry.CloudGUID != asset.CloudAssetGUID {
+		t.Errorf("Expected cloud asset GUID '%s', got '%s'", asset.CloudAssetGUID, entry.CloudGUID)
+	}
+	if entry.PhotosID != "42" {
+		t.Errorf("Expected Photos ID '42', got '%s'", entry.PhotosID)
+	}
 }
 
 func TestFinalize(t *testing.T) {
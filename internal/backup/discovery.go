@@ -0,0 +1,180 @@
+package backup
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grantbirki/gh-photos/internal/plist"
+)
+
+// BackupSummary describes one device backup found by DiscoverBackups
+type BackupSummary struct {
+	Path           string    `json:"path"`
+	UDID           string    `json:"udid"`
+	DeviceName     string    `json:"device_name,omitempty"`
+	ProductType    string    `json:"product_type,omitempty"`
+	IOSVersion     string    `json:"ios_version,omitempty"`
+	LastBackupDate time.Time `json:"last_backup_date"`
+	Encrypted      bool      `json:"encrypted"`
+	Size           int64     `json:"size"`
+}
+
+// DiscoverBackups lists the device backups under root, which may be a MobileSync directory,
+// its Backup directory, or a single backup. Backups are read from Info.plist and Manifest.plist
+// and returned ordered by device name, then UDID.
+func DiscoverBackups(root string) ([]BackupSummary, error) {
+	backups, err := discoverBackups(root)
+	if err != nil {
+		return nil, err
+	}
+	for i := range backups {
+		backups[i].Size = backupSize(backups[i].Path)
+	}
+	return backups, nil
+}
+
+// discoverBackups lists the device backups under root like DiscoverBackups, without measuring them
+func discoverBackups(root string) ([]BackupSummary, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("backup root does not exist: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("backup root is not a directory")
+	}
+
+	var backups []BackupSummary
+	for _, dir := range backupCandidates(root) {
+		backups = append(backups, *summarizeBackup(dir))
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		a, b := strings.ToLower(backups[i].DeviceName), strings.ToLower(backups[j].DeviceName)
+		if a != b {
+			return a < b
+		}
+		return backups[i].UDID < backups[j].UDID
+	})
+	return backups, nil
+}
+
+// SelectBackup returns the path of the backup under root whose device name (case-insensitive)
+// or UDID matches device. It's an error if no backup or more than one backup matches. Only the
+// property lists of each backup are read.
+func SelectBackup(root, device string) (string, error) {
+	backups, err := discoverBackups(root)
+	if err != nil {
+		return "", err
+	}
+	if len(backups) == 0 {
+		return "", fmt.Errorf("no device backups found in %s", root)
+	}
+
+	var matches []BackupSummary
+	for _, b := range backups {
+		if strings.EqualFold(b.UDID, device) || strings.EqualFold(b.DeviceName, device) {
+			matches = append(matches, b)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0].Path, nil
+	case 0:
+		var available []string
+		for _, b := range backups {
+			available = append(available, fmt.Sprintf("%s (%s)", b.DeviceName, b.UDID))
+		}
+		return "", fmt.Errorf("no backup for device %q in %s; available: %s", device, root, strings.Join(available, ", "))
+	default:
+		return "", fmt.Errorf("%d backups match device %q in %s - use the UDID to pick one", len(matches), device, root)
+	}
+}
+
+// backupCandidates returns the backup directories in root: root itself, or the backups in
+// root/Backup or directly in root
+func backupCandidates(root string) []string {
+	if isValidBackupDir(root) {
+		return []string{root}
+	}
+
+	var candidates []string
+	for _, parent := range []string{filepath.Join(root, "Backup"), root} {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			dir := filepath.Join(parent, entry.Name())
+			if entry.IsDir() && isValidBackupDir(dir) {
+				candidates = append(candidates, dir)
+			}
+		}
+		if len(candidates) > 0 {
+			break
+		}
+	}
+	return candidates
+}
+
+// summarizeBackup reads the device details of the backup in dir from its property lists
+func summarizeBackup(dir string) *BackupSummary {
+	summary := &BackupSummary{Path: dir, UDID: filepath.Base(dir)}
+
+	if info, err := readPlistFile(filepath.Join(dir, "Info.plist")); err == nil {
+		if udid, ok := info["Unique Identifier"].(string); ok && udid != "" {
+			summary.UDID = udid
+		}
+		summary.DeviceName, _ = info["Device Name"].(string)
+		summary.ProductType, _ = info["Product Type"].(string)
+		summary.IOSVersion, _ = info["Product Version"].(string)
+		summary.LastBackupDate, _ = info["Last Backup Date"].(time.Time)
+	}
+
+	if manifest, err := readPlistFile(filepath.Join(dir, "Manifest.plist")); err == nil {
+		summary.Encrypted, _ = manifest["IsEncrypted"].(bool)
+		if summary.LastBackupDate.IsZero() {
+			summary.LastBackupDate, _ = manifest["Date"].(time.Time)
+		}
+	}
+
+	return summary
+}
+
+// backupSize returns the total size of the files in dir. Files and directories that can't be
+// read are left out rather than failing the listing.
+func backupSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// readPlistFile decodes a property list file whose root is a dictionary
+func readPlistFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := plist.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	dict, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a dictionary", filepath.Base(path))
+	}
+	return dict, nil
+}
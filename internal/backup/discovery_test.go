@@ -0,0 +1,124 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createDeviceBackup writes the plists and an empty Manifest.db of a device backup under parent
+func createDeviceBackup(t *testing.T, parent, udid, name string, encrypted bool) string {
+	t.Helper()
+	dir := filepath.Join(parent, udid)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	info := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>Device Name</key><string>%s</string>
+	<key>Product Type</key><string>iPhone15,2</string>
+	<key>Product Version</key><string>17.5.1</string>
+	<key>Last Backup Date</key><date>2024-06-01T12:30:00Z</date>
+	<key>Unique Identifier</key><string>%s</string>
+</dict>
+</plist>`, name, udid)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Info.plist"), []byte(info), 0644))
+
+	encryptedValue := "<false/>"
+	if encrypted {
+		encryptedValue = "<true/>"
+	}
+	manifest := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0"><dict><key>IsEncrypted</key>` + encryptedValue + `</dict></plist>`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Manifest.plist"), []byte(manifest), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Manifest.db"), []byte("db"), 0644))
+	return dir
+}
+
+func TestDiscoverBackups(t *testing.T) {
+	root := t.TempDir()
+	parent := filepath.Join(root, "Backup")
+	work := createDeviceBackup(t, parent, "00008110-000A", "Work iPhone", true)
+	home := createDeviceBackup(t, parent, "00008110-000B", "home iPhone", false)
+	require.NoError(t, os.MkdirAll(filepath.Join(parent, "not-a-backup"), 0755))
+
+	backups, err := DiscoverBackups(root)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	assert.Equal(t, home, backups[0].Path)
+	assert.Equal(t, "home iPhone", backups[0].DeviceName)
+	assert.False(t, backups[0].Encrypted)
+
+	assert.Equal(t, BackupSummary{
+		Path:           work,
+		UDID:           "00008110-000A",
+		DeviceName:     "Work iPhone",
+		ProductType:    "iPhone15,2",
+		IOSVersion:     "17.5.1",
+		LastBackupDate: time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC),
+		Encrypted:      true,
+		Size:           backups[1].Size,
+	}, backups[1])
+	assert.Positive(t, backups[1].Size)
+
+	// The Backup directory and a single backup are accepted as well
+	fromParent, err := DiscoverBackups(parent)
+	require.NoError(t, err)
+	assert.Equal(t, backups, fromParent)
+	single, err := DiscoverBackups(work)
+	require.NoError(t, err)
+	require.Len(t, single, 1)
+	assert.Equal(t, work, single[0].Path)
+}
+
+func TestSelectBackup(t *testing.T) {
+	root := t.TempDir()
+	work := createDeviceBackup(t, root, "00008110-000A", "Work iPhone", false)
+	home := createDeviceBackup(t, root, "00008110-000B", "Home iPhone", false)
+	createDeviceBackup(t, root, "00008110-000C", "Spare", false)
+	createDeviceBackup(t, root, "00008110-000D", "Spare", false)
+
+	path, err := SelectBackup(root, "work iphone")
+	require.NoError(t, err)
+	assert.Equal(t, work, path)
+
+	path, err = SelectBackup(root, "00008110-000B")
+	require.NoError(t, err)
+	assert.Equal(t, home, path)
+
+	_, err = SelectBackup(root, "Spare")
+	assert.ErrorContains(t, err, "2 backups match")
+
+	_, err = SelectBackup(root, "iPad")
+	assert.ErrorContains(t, err, "Work iPhone (00008110-000A)")
+
+	_, err = SelectBackup(t.TempDir(), "Work iPhone")
+	assert.ErrorContains(t, err, "no device backups found")
+}
+
+func TestDiscoverBackupsSkipsUnreadableFiles(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions aren't enforced for root")
+	}
+	root := t.TempDir()
+	work := createDeviceBackup(t, root, "00008110-000A", "Work iPhone", false)
+	locked := filepath.Join(work, "ab")
+	require.NoError(t, os.MkdirAll(locked, 0755))
+	require.NoError(t, os.Chmod(locked, 0))
+	t.Cleanup(func() { os.Chmod(locked, 0755) })
+
+	backups, err := DiscoverBackups(root)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Positive(t, backups[0].Size)
+
+	path, err := SelectBackup(root, "Work iPhone")
+	require.NoError(t, err)
+	assert.Equal(t, work, path)
+}
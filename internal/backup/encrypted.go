@@ -0,0 +1,229 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/grantbirki/gh-photos/internal/encryption"
+)
+
+// ErrPasswordRequired is returned when an encrypted backup is opened without a password
+var ErrPasswordRequired = errors.New("backup is encrypted and no password was provided")
+
+// EncryptedBackup gives access to the contents of an encrypted backup. Opening it unlocks the
+// keybag from Manifest.plist with the backup password and decrypts Manifest.db to a private
+// temporary directory; files are decrypted on demand.
+type EncryptedBackup struct {
+	backupPath string
+	keybag     *encryption.Keybag
+	manifest   *ManifestDB
+	tempDir    string
+}
+
+// OpenEncryptedBackup unlocks an encrypted backup with its password
+func OpenEncryptedBackup(backupPath, password string) (*EncryptedBackup, error) {
+	if password == "" {
+		return nil, ErrPasswordRequired
+	}
+
+	manifestPlist, err := readManifestPlist(backupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	keybagData, ok := manifestPlist["BackupKeyBag"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("Manifest.plist has no BackupKeyBag")
+	}
+	manifestKey, ok := manifestPlist["ManifestKey"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("Manifest.plist has no ManifestKey")
+	}
+
+	keybag, err := encryption.ParseKeybag(keybagData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse backup keybag: %w", err)
+	}
+	if err := keybag.Unlock(password); err != nil {
+		return nil, fmt.Errorf("failed to unlock backup: %w", err)
+	}
+
+	key, err := keybag.UnwrapPrefixedKey(manifestKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap Manifest.db key: %w", err)
+	}
+
+	// Decrypted databases stay in a directory only the current user can read
+	tempDir, err := os.MkdirTemp("", "gh-photos-decrypted-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	eb := &EncryptedBackup{
+		backupPath: backupPath,
+		keybag:     keybag,
+		tempDir:    tempDir,
+	}
+
+	decryptedManifest := filepath.Join(tempDir, "Manifest.db")
+	if err := decryptFileWithKey(filepath.Join(backupPath, "Manifest.db"), decryptedManifest, key, -1); err != nil {
+		eb.Close()
+		return nil, fmt.Errorf("failed to decrypt Manifest.db: %w", err)
+	}
+
+	manifest, err := openManifestDBFile(decryptedManifest)
+	if err != nil {
+		eb.Close()
+		return nil, fmt.Errorf("failed to open decrypted Manifest.db: %w", err)
+	}
+	eb.manifest = manifest
+
+	return eb, nil
+}
+
+// Manifest returns the decrypted Manifest.db
+func (eb *EncryptedBackup) Manifest() *ManifestDB {
+	return eb.manifest
+}
+
+// TempDir returns the private directory holding decrypted databases
+func (eb *EncryptedBackup) TempDir() string {
+	return eb.tempDir
+}
+
+// DecryptFile decrypts the backup file described by record and writes its contents to w.
+// Returns the number of plaintext bytes written.
+func (eb *EncryptedBackup) DecryptFile(record *FileRecord, w io.Writer) (int64, error) {
+	info, err := decodeFileInfo(record.File)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file metadata: %w", err)
+	}
+	return eb.decryptFile(record, info, w)
+}
+
+// decryptFile decrypts a backup file whose metadata has already been decoded
+func (eb *EncryptedBackup) decryptFile(record *FileRecord, info *fileInfo, w io.Writer) (int64, error) {
+	if len(info.EncryptionKey) == 0 {
+		return 0, fmt.Errorf("file has no encryption key")
+	}
+
+	key, err := eb.keybag.UnwrapPrefixedKey(info.EncryptionKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to unwrap file key: %w", err)
+	}
+
+	source, err := os.Open(eb.manifest.getActualFilePath(eb.backupPath, record.FileID))
+	if err != nil {
+		return 0, fmt.Errorf("source file not found: %w", err)
+	}
+	defer source.Close()
+
+	written, err := encryption.DecryptStream(w, source, key, info.Size)
+	if err != nil {
+		return written, err
+	}
+	if info.Size >= 0 && written != info.Size {
+		return written, fmt.Errorf("decrypted %d bytes, expected %d", written, info.Size)
+	}
+	return written, nil
+}
+
+// DecryptToFile decrypts the backup file described by record to targetPath
+func (eb *EncryptedBackup) DecryptToFile(record *FileRecord, targetPath string) (int64, error) {
+	info, err := decodeFileInfo(record.File)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file metadata: %w", err)
+	}
+	return eb.decryptToFile(record, info, targetPath)
+}
+
+// decryptToFile decrypts a backup file whose metadata has already been decoded to targetPath
+func (eb *EncryptedBackup) decryptToFile(record *FileRecord, info *fileInfo, targetPath string) (int64, error) {
+	target, err := os.Create(targetPath)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := eb.decryptFile(record, info, target)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	return written, err
+}
+
+// DecryptPhotosDatabase decrypts Photos.sqlite, together with its write-ahead log when the
+// backup has one, into the temp directory and returns the path of the decrypted database
+func (eb *EncryptedBackup) DecryptPhotosDatabase() (string, error) {
+	record, err := eb.manifest.findPhotosDatabaseRecord()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(eb.tempDir, "PhotoData")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	dbPath := filepath.Join(dir, "Photos.sqlite")
+	if _, err := eb.DecryptToFile(record, dbPath); err != nil {
+		return "", fmt.Errorf("failed to decrypt Photos.sqlite: %w", err)
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		companion, err := eb.manifest.findFile(record.Domain, record.RelativePath+suffix)
+		if err != nil || companion == nil {
+			continue
+		}
+		if _, err := eb.DecryptToFile(companion, dbPath+suffix); err != nil {
+			return "", fmt.Errorf("failed to decrypt Photos.sqlite%s: %w", suffix, err)
+		}
+	}
+
+	return dbPath, nil
+}
+
+// Close closes the decrypted Manifest.db and removes every decrypted temp file
+func (eb *EncryptedBackup) Close() error {
+	var err error
+	if eb.manifest != nil {
+		err = eb.manifest.Close()
+	}
+	if eb.tempDir != "" {
+		if removeErr := os.RemoveAll(eb.tempDir); err == nil {
+			err = removeErr
+		}
+	}
+	return err
+}
+
+// decryptFileWithKey decrypts sourcePath to targetPath with an already unwrapped key
+func decryptFileWithKey(sourcePath, targetPath string, key []byte, size int64) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = encryption.DecryptStream(target, source, key, size)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// readManifestPlist decodes the backup's Manifest.plist (XML or binary)
+func readManifestPlist(backupPath string) (map[string]any, error) {
+	dict, err := readPlistFile(filepath.Join(backupPath, "Manifest.plist"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Manifest.plist: %w", err)
+	}
+	return dict, nil
+}
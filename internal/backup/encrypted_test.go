@@ -0,0 +1,257 @@
+package backup
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantbirki/gh-photos/internal/encryption"
+	"github.com/grantbirki/gh-photos/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixturePassword = "correct horse battery staple"
+
+// encryptedFixtureFile is a file stored in the synthetic encrypted backup
+type encryptedFixtureFile struct {
+	fileID       string
+	domain       string
+	relativePath string
+	content      []byte
+}
+
+func keybagEntry(tag string, value []byte) []byte {
+	out := make([]byte, 8, 8+len(value))
+	copy(out, tag)
+	binary.BigEndian.PutUint32(out[4:], uint32(len(value)))
+	return append(out, value...)
+}
+
+func keybagUint32(tag string, v uint32) []byte {
+	value := make([]byte, 4)
+	binary.BigEndian.PutUint32(value, v)
+	return keybagEntry(tag, value)
+}
+
+// mbFileArchive builds the keyed archive stored in Files.file for an encrypted file
+func mbFileArchive(size int, encryptionKey []byte) []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>$archiver</key><string>NSKeyedArchiver</string>
+	<key>$top</key><dict><key>root</key><dict><key>CF$UID</key><integer>1</integer></dict></dict>
+	<key>$objects</key>
+	<array>
+		<string>$null</string>
+		<dict>
+			<key>Size</key><integer>%d</integer>
+			<key>ProtectionClass</key><integer>3</integer>
+			<key>EncryptionKey</key><dict><key>CF$UID</key><integer>2</integer></dict>
+		</dict>
+		<dict><key>NS.data</key><data>%s</data></dict>
+	</array>
+</dict>
+</plist>`, size, base64.StdEncoding.EncodeToString(encryptionKey)))
+}
+
+// createEncryptedFixture writes a synthetic encrypted backup protected by fixturePassword
+func createEncryptedFixture(t *testing.T, files []encryptedFixtureFile) string {
+	t.Helper()
+	backupDir := t.TempDir()
+
+	// Keybag with class keys 3 and 4 wrapped by the password-derived key
+	salt := bytes.Repeat([]byte{0x5A}, 20)
+	dpsl := bytes.Repeat([]byte{0xA5}, 20)
+	kb := &encryption.Keybag{Salt: salt, Iterations: 10, DoubleProtectionSalt: dpsl, DoubleProtectionIter: 10}
+	passcodeKey, err := kb.DeriveKey(fixturePassword)
+	require.NoError(t, err)
+
+	classKeys := map[uint32][]byte{
+		3: bytes.Repeat([]byte{0x33}, 32),
+		4: bytes.Repeat([]byte{0x44}, 32),
+	}
+	var keybag []byte
+	keybag = append(keybag, keybagUint32("VERS", 3)...)
+	keybag = append(keybag, keybagUint32("TYPE", 1)...)
+	keybag = append(keybag, keybagEntry("UUID", bytes.Repeat([]byte{0xEE}, 16))...)
+	keybag = append(keybag, keybagEntry("SALT", salt)...)
+	keybag = append(keybag, keybagUint32("ITER", 10)...)
+	keybag = append(keybag, keybagEntry("DPSL", dpsl)...)
+	keybag = append(keybag, keybagUint32("DPIC", 10)...)
+	for _, class := range []uint32{3, 4} {
+		wrapped, err := encryption.AESWrap(passcodeKey, classKeys[class])
+		require.NoError(t, err)
+		keybag = append(keybag, keybagEntry("UUID", bytes.Repeat([]byte{byte(class)}, 16))...)
+		keybag = append(keybag, keybagUint32("CLAS", class)...)
+		keybag = append(keybag, keybagUint32("WRAP", encryption.WrapPasscode)...)
+		keybag = append(keybag, keybagUint32("KTYP", 0)...)
+		keybag = append(keybag, keybagEntry("WPKY", wrapped)...)
+	}
+
+	wrapWithClass := func(class uint32, key []byte) []byte {
+		wrapped, err := encryption.AESWrap(classKeys[class], key)
+		require.NoError(t, err)
+		prefix := make([]byte, 4)
+		binary.LittleEndian.PutUint32(prefix, class)
+		return append(prefix, wrapped...)
+	}
+
+	// Plain Manifest.db, encrypted once populated
+	plainManifest := filepath.Join(t.TempDir(), "Manifest.db")
+	db, err := sql.Open("sqlite", plainManifest)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE Files (fileID TEXT PRIMARY KEY, domain TEXT, relativePath TEXT, flags INTEGER, file BLOB)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO Files VALUES ('dir0000000000000000000000000000000000000', 'CameraRollDomain', 'Media/DCIM', 2, NULL)`)
+	require.NoError(t, err)
+
+	for i, file := range files {
+		fileKey := bytes.Repeat([]byte{byte(0x60 + i)}, 32)
+		ciphertext, err := encryption.EncryptData(fileKey, file.content)
+		require.NoError(t, err)
+
+		blobPath := filepath.Join(backupDir, file.fileID[:2], file.fileID)
+		require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0755))
+		require.NoError(t, os.WriteFile(blobPath, ciphertext, 0644))
+
+		_, err = db.Exec(`INSERT INTO Files VALUES (?, ?, ?, 1, ?)`,
+			file.fileID, file.domain, file.relativePath, mbFileArchive(len(file.content), wrapWithClass(3, fileKey)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	manifestKey := bytes.Repeat([]byte{0x77}, 32)
+	plainManifestData, err := os.ReadFile(plainManifest)
+	require.NoError(t, err)
+	encryptedManifest, err := encryption.EncryptData(manifestKey, plainManifestData)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, "Manifest.db"), encryptedManifest, 0644))
+
+	manifestPlist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>BackupKeyBag</key>
+	<data>%s</data>
+	<key>IsEncrypted</key>
+	<true/>
+	<key>ManifestKey</key>
+	<data>%s</data>
+</dict>
+</plist>`, base64.StdEncoding.EncodeToString(keybag), base64.StdEncoding.EncodeToString(wrapWithClass(4, manifestKey)))
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, "Manifest.plist"), []byte(manifestPlist), 0644))
+
+	return backupDir
+}
+
+func fixtureFiles() []encryptedFixtureFile {
+	return []encryptedFixtureFile{
+		{
+			fileID:       "0a1b2c3d4e5f60718293a4b5c6d7e8f901234567",
+			domain:       "CameraRollDomain",
+			relativePath: "Media/DCIM/100APPLE/IMG_0001.JPG",
+			content:      bytes.Repeat([]byte("jpeg data "), 1000),
+		},
+		{
+			fileID:       "12b9effdbb2d6a5fe2b5b6d3fc5e4c5d8e7a1f00",
+			domain:       "CameraRollDomain",
+			relativePath: "Media/PhotoData/Photos.sqlite",
+			content:      []byte("not really a database"),
+		},
+	}
+}
+
+func TestOpenEncryptedBackup(t *testing.T) {
+	backupDir := createEncryptedFixture(t, fixtureFiles())
+
+	t.Run("correct_password", func(t *testing.T) {
+		eb, err := OpenEncryptedBackup(backupDir, fixturePassword)
+		require.NoError(t, err)
+		tempDir := eb.TempDir()
+
+		records, err := eb.Manifest().GetAllFiles()
+		require.NoError(t, err)
+		assert.Len(t, records, 3)
+
+		dbPath, err := eb.DecryptPhotosDatabase()
+		require.NoError(t, err)
+		content, err := os.ReadFile(dbPath)
+		require.NoError(t, err)
+		assert.Equal(t, "not really a database", string(content))
+
+		require.NoError(t, eb.Close())
+		_, err = os.Stat(tempDir)
+		assert.True(t, os.IsNotExist(err), "decrypted files should be removed on close")
+	})
+
+	t.Run("wrong_password", func(t *testing.T) {
+		_, err := OpenEncryptedBackup(backupDir, "wrong")
+		assert.ErrorIs(t, err, encryption.ErrWrongPassword)
+	})
+
+	t.Run("no_password", func(t *testing.T) {
+		_, err := OpenEncryptedBackup(backupDir, "")
+		assert.ErrorIs(t, err, ErrPasswordRequired)
+	})
+}
+
+func TestExtractEncryptedBackup(t *testing.T) {
+	files := fixtureFiles()
+	backupDir := createEncryptedFixture(t, files)
+	outputDir := filepath.Join(t.TempDir(), "extracted")
+
+	extractor, err := CreateExtractor(ExtractConfig{
+		BackupPath: backupDir,
+		OutputPath: outputDir,
+		Verify:     true,
+		Password:   fixturePassword,
+		Logger:     logger.New(logger.Config{Level: logger.LevelInfo, Output: io.Discard}),
+	})
+	require.NoError(t, err)
+	defer extractor.Close()
+
+	summary, err := extractor.Extract()
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.ExtractedFiles)
+	assert.Equal(t, 1, summary.SkippedFiles)
+	assert.Equal(t, 0, summary.FailedFiles)
+
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(outputDir, file.domain, file.relativePath))
+		require.NoError(t, err)
+		assert.Equal(t, file.content, content)
+	}
+}
+
+func TestExtractEncryptedBackupTruncatedFile(t *testing.T) {
+	files := fixtureFiles()[:1]
+	backupDir := createEncryptedFixture(t, files)
+
+	// Drop the last block so the decrypted size no longer matches the recorded size
+	blobPath := filepath.Join(backupDir, files[0].fileID[:2], files[0].fileID)
+	data, err := os.ReadFile(blobPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(blobPath, data[:len(data)-32], 0644))
+
+	extractor, err := CreateExtractor(ExtractConfig{
+		BackupPath: backupDir,
+		OutputPath: filepath.Join(t.TempDir(), "extracted"),
+		Password:   fixturePassword,
+		Logger:     logger.New(logger.Config{Level: logger.LevelInfo, Output: io.Discard}),
+	})
+	require.NoError(t, err)
+	defer extractor.Close()
+
+	summary, err := extractor.Extract()
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.FailedFiles)
+	require.Len(t, summary.CriticalErrors, 1)
+	assert.Contains(t, summary.CriticalErrors[0], "expected")
+}
@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+)
+
+// escapeChar starts an escape sequence: the character's byte value as two hex digits (":" -> "%3A")
+const escapeChar = '%'
+
+// needsEscape reports whether c can't be stored in a file name on every filesystem an extraction
+// may be written to. These are the characters Windows, exFAT and FAT32 reject.
+func needsEscape(c byte) bool {
+	return c < 0x20 || strings.IndexByte(`<>:"\|?*`, c) >= 0
+}
+
+// escapePath makes a slash-separated backup path safe to write on any filesystem. Each path
+// component holding a rejected character, a trailing dot or space (which Windows strips), or
+// the escape character itself has those bytes replaced by %XX escapes; other components are
+// left alone, and the original path is recorded in the extraction metadata's escaped_paths.
+// It reports whether the path was changed.
+func escapePath(relativePath string) (string, bool) {
+	components := strings.Split(relativePath, "/")
+	changed := false
+	for i, component := range components {
+		if escaped, ok := escapeComponent(component); ok {
+			components[i] = escaped
+			changed = true
+		}
+	}
+	if !changed {
+		return relativePath, false
+	}
+	return strings.Join(components, "/"), true
+}
+
+// escapeComponent escapes a single path component, reporting whether it needed escaping
+func escapeComponent(component string) (string, bool) {
+	trailing := len(strings.TrimRight(component, ". "))
+	needed := trailing < len(component) || strings.IndexByte(component, escapeChar) >= 0
+	for i := 0; i < len(component) && !needed; i++ {
+		needed = needsEscape(component[i])
+	}
+	if !needed {
+		return component, false
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(component); i++ {
+		c := component[i]
+		if needsEscape(c) || c == escapeChar || i >= trailing {
+			fmt.Fprintf(&b, "%c%02X", escapeChar, c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), true
+}
@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantbirki/gh-photos/internal/logger"
+	"github.com/grantbirki/gh-photos/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"Media/DCIM/100APPLE/IMG_0001.HEIC", "Media/DCIM/100APPLE/IMG_0001.HEIC"},
+		{"Library/Caches/https:example.com?id=1", "Library/Caches/https%3Aexample.com%3Fid=1"},
+		{"Documents/50% off <sale>.txt", "Documents/50%25 off %3Csale%3E.txt"},
+		{"Documents/name./file", "Documents/name%2E/file"},
+		{"Documents/trailing ", "Documents/trailing%20"},
+		{"Documents/back\\slash|pipe", "Documents/back%5Cslash%7Cpipe"},
+		{"Documents/tab\there", "Documents/tab%09here"},
+	}
+	for _, tt := range tests {
+		escaped, changed := escapePath(tt.path)
+		assert.Equal(t, tt.expected, escaped, tt.path)
+		assert.Equal(t, tt.path != tt.expected, changed, tt.path)
+	}
+}
+
+func TestExtractEscapedNames(t *testing.T) {
+	backupDir := createMultiFileFixture(t, 0)
+	files := []struct{ domain, path, content string }{
+		{"CameraRollDomain", "Media/DCIM/100APPLE/IMG:0001.JPG", "photo"},
+		{"CameraRollDomain", "Media/DCIM/100APPLE/IMG_0002.JPG", "plain"},
+	}
+	db, err := OpenManifestDB(backupDir)
+	require.NoError(t, err)
+	for i, file := range files {
+		fileID := fmt.Sprintf("%02x%038d", i, i)
+		blobPath := filepath.Join(backupDir, fileID[:2], fileID)
+		require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0755))
+		require.NoError(t, os.WriteFile(blobPath, []byte(file.content), 0644))
+		_, err = db.db.Exec(`INSERT INTO Files VALUES (?, ?, ?, 1, ?)`, fileID, file.domain, file.path,
+			plainMBFileArchive(len(file.content), 0o100644, 1700000000, nil))
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	outputDir := t.TempDir()
+	log := logger.New(logger.Config{Level: logger.LevelError, Output: io.Discard})
+	extractor, err := CreateExtractor(ExtractConfig{BackupPath: backupDir, OutputPath: outputDir, Logger: log})
+	require.NoError(t, err)
+	defer extractor.Close()
+
+	summary, err := extractor.Extract()
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.ExtractedFiles)
+	assert.Equal(t, 0, summary.FailedFiles)
+	assert.Equal(t, map[string]string{
+		"CameraRollDomain/Media/DCIM/100APPLE/IMG:0001.JPG": "CameraRollDomain/Media/DCIM/100APPLE/IMG%3A0001.JPG",
+	}, summary.EscapedPaths)
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "CameraRollDomain", "Media", "DCIM", "100APPLE", "IMG%3A0001.JPG"))
+	require.NoError(t, err)
+	assert.Equal(t, "photo", string(content))
+
+	// The extracted parser translates the escaped name back to the asset recorded in Photos.sqlite
+	metadata, err := json.Marshal(map[string]any{
+		"assets": []*types.Asset{{
+			ID:         "1",
+			SourcePath: "/backup/DCIM/100APPLE/IMG:0001.JPG",
+			Filename:   "IMG:0001.JPG",
+			Type:       types.AssetTypePhoto,
+		}},
+		"escaped_paths": summary.EscapedPaths,
+	})
+	require.NoError(t, err)
+	metadataPath := filepath.Join(outputDir, "extraction-metadata.json")
+	require.NoError(t, os.WriteFile(metadataPath, metadata, 0644))
+
+	parser, err := CreateExtractedBackupParser(outputDir, metadataPath, log)
+	require.NoError(t, err)
+	require.Len(t, parser.extractedAssets, 1)
+	assert.Equal(t, filepath.Join(outputDir, "CameraRollDomain", "Media", "DCIM", "100APPLE", "IMG%3A0001.JPG"), parser.extractedAssets[0].SourcePath)
+	assert.Equal(t, "IMG:0001.JPG", parser.extractedAssets[0].Filename)
+}
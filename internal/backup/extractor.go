@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/grantbirki/gh-photos/internal/logger"
+	"github.com/grantbirki/gh-photos/internal/utils"
 )
 
 // ExtractConfig holds configuration for backup extraction
@@ -19,14 +22,29 @@ type ExtractConfig struct {
 	SkipExisting bool
 	Verify       bool
 	Progress     bool
+	Password     string   // password of an encrypted backup
+	Workers      int      // number of files extracted concurrently (default: defaultExtractWorkers)
+	Resume       bool     // skip files recorded in the output directory's extraction journal
+	Domains      []string // domain globs to extract (default: all domains)
+	Include      []string // relative path globs to extract (default: all paths)
+	Exclude      []string // relative path globs to leave out
+	MediaOnly    bool     // add the MediaOnlyFilter preset to the filters above
 	Logger       *logger.Logger
 }
 
+// defaultExtractWorkers is the worker count used when ExtractConfig.Workers isn't set
+const defaultExtractWorkers = 4
+
+// partialSuffix marks files that are still being written during extraction
+const partialSuffix = ".partial"
+
 // ExtractSummary provides statistics about the extraction operation
 type ExtractSummary struct {
 	TotalFiles             int           `json:"total_files"`
 	ExtractedFiles         int           `json:"extracted_files"`
+	EstimatedSize          int64         `json:"estimated_size"`
 	SkippedFiles           int           `json:"skipped_files"`
+	ResumedFiles           int           `json:"resumed_files,omitempty"`
 	FailedFiles            int           `json:"failed_files"`
 	DomainsFound           int           `json:"domains_found"`
 	TotalSize              int64         `json:"total_size"`
@@ -35,13 +53,18 @@ type ExtractSummary struct {
 	Errors                 []string      `json:"errors,omitempty"`
 	FilesystemCompatErrors int           `json:"filesystem_compat_errors"`
 	CriticalErrors         []string      `json:"critical_errors,omitempty"`
+	// EscapedPaths maps the Domain/relativePath of each file whose name the filesystem could reject
+	// to the escaped path it was written to under the output directory (see escapePath)
+	EscapedPaths map[string]string `json:"escaped_paths,omitempty"`
 }
 
 // Extractor handles iTunes backup extraction
 type Extractor struct {
-	config   ExtractConfig
-	manifest *ManifestDB
-	summary  ExtractSummary
+	config    ExtractConfig
+	manifest  *ManifestDB
+	encrypted *EncryptedBackup // set when the backup is encrypted
+	mu        sync.Mutex       // guards summary while workers extract files
+	summary   ExtractSummary
 }
 
 // CreateExtractor creates a new backup extractor
@@ -52,16 +75,26 @@ func CreateExtractor(config ExtractConfig) (*Extractor, error) {
 	}
 
 	// Check if backup is encrypted
-	if encrypted, err := isBackupEncrypted(config.BackupPath); err != nil {
+	encrypted, err := isBackupEncrypted(config.BackupPath)
+	if err != nil {
 		return nil, fmt.Errorf("failed to check encryption status: %w", err)
-	} else if encrypted {
-		return nil, fmt.Errorf("encrypted backups are not supported - this tool only works with unencrypted iTunes/Finder backups")
 	}
 
-	// Open Manifest.db
-	manifest, err := OpenManifestDB(config.BackupPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open Manifest.db: %w", err)
+	// Open Manifest.db, decrypting it first for encrypted backups
+	var manifest *ManifestDB
+	var encryptedBackup *EncryptedBackup
+	if encrypted {
+		encryptedBackup, err = OpenEncryptedBackup(config.BackupPath, config.Password)
+		if err != nil {
+			return nil, err
+		}
+		manifest = encryptedBackup.Manifest()
+		config.Logger.Info("Unlocked encrypted backup")
+	} else {
+		manifest, err = OpenManifestDB(config.BackupPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Manifest.db: %w", err)
+		}
 	}
 
 	// Validate database schema (following Rust implementation pattern)
@@ -74,6 +107,11 @@ func CreateExtractor(config ExtractConfig) (*Extractor, error) {
 			// This is likely a test database or empty database, skip validation
 			config.Logger.Debug("Skipping schema validation for empty database")
 		} else {
+			if encryptedBackup != nil {
+				encryptedBackup.Close()
+			} else {
+				manifest.Close()
+			}
 			return nil, fmt.Errorf("incompatible Manifest.db schema: %w", err)
 		}
 	}
@@ -82,16 +120,23 @@ func CreateExtractor(config ExtractConfig) (*Extractor, error) {
 	if config.OutputPath == "" {
 		config.OutputPath = "./extracted-backup"
 	}
+	if config.Workers <= 0 {
+		config.Workers = defaultExtractWorkers
+	}
 
 	return &Extractor{
-		config:   config,
-		manifest: manifest,
-		summary:  ExtractSummary{},
+		config:    config,
+		manifest:  manifest,
+		encrypted: encryptedBackup,
+		summary:   ExtractSummary{},
 	}, nil
 }
 
 // Close releases resources
 func (e *Extractor) Close() error {
+	if e.encrypted != nil {
+		return e.encrypted.Close()
+	}
 	if e.manifest != nil {
 		return e.manifest.Close()
 	}
@@ -109,14 +154,28 @@ func (e *Extractor) Extract() (*ExtractSummary, error) {
 		"backup_path", e.config.BackupPath,
 		"output_path", e.config.OutputPath)
 
-	// Get all files from manifest
-	files, err := e.manifest.GetAllFiles()
+	// Get the selected files from manifest; filters are applied in the query
+	filter := e.fileFilter()
+	files, err := e.manifest.GetFiles(filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read manifest files: %w", err)
 	}
 
 	e.summary.TotalFiles = len(files)
-	e.config.Logger.Info("Found files in backup", "count", len(files))
+	if filter.IsEmpty() {
+		e.config.Logger.Info("Found files in backup", "count", len(files))
+	} else {
+		e.config.Logger.Info("Found files matching filters",
+			"count", len(files),
+			"domains", strings.Join(filter.Domains, ","),
+			"include", strings.Join(filter.Include, ","),
+			"exclude", strings.Join(filter.Exclude, ","))
+	}
+
+	e.summary.EstimatedSize = e.estimateSize(files)
+	e.config.Logger.Info("Estimated size to extract",
+		"size", humanizeBytes(e.summary.EstimatedSize),
+		"bytes", e.summary.EstimatedSize)
 
 	// Count unique domains
 	domains := make(map[string]bool)
@@ -130,53 +189,40 @@ func (e *Extractor) Extract() (*ExtractSummary, error) {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	// Open the journal of completed files; with Resume, files from the previous run are skipped
+	journal, err := openExtractionJournal(utils.CreateBackupPaths(e.config.OutputPath).ExtractionJournal(), e.config.Resume)
+	if err != nil {
+		return nil, err
+	}
+	defer journal.Close()
+	if e.config.Resume {
+		e.config.Logger.Info("Resuming extraction", "completed_files", journal.Count())
+	}
+
 	// Extract files with improved progress reporting (inspired by Rust implementation)
 	if e.config.Progress {
 		e.config.Logger.Info("Starting file extraction phase",
-			"total_files", len(files))
-	}
-
-	for i, file := range files {
-		if e.config.Progress && (i%50 == 0 || i == len(files)-1) {
-			progress := float64(i+1) / float64(len(files)) * 100
-			e.config.Logger.Info("Extracting files",
-				"progress", fmt.Sprintf("%.1f%%", progress),
-				"processed", fmt.Sprintf("%d/%d", i+1, len(files)),
-				"current_domain", file.Domain,
-				"current_file", filepath.Base(file.RelativePath))
-		}
-
-		// Debug log for individual file processing (only visible with --log-level debug)
-		e.config.Logger.Debug("Processing file",
-			"domain", file.Domain,
-			"relative_path", file.RelativePath,
-			"file_id", file.FileID,
-			"index", fmt.Sprintf("%d/%d", i+1, len(files)))
-
-		if err := e.extractFile(&file); err != nil {
-			e.summary.FailedFiles++
-
-			// Categorize the error
-			if isFilesystemCompatibilityError(err, file.RelativePath) {
-				e.summary.FilesystemCompatErrors++
-				// Only log filesystem compatibility errors at debug level
-				e.config.Logger.Debug("Skipped file due to filesystem compatibility",
-					"domain", file.Domain,
-					"path", file.RelativePath,
-					"error", err)
-			} else {
-				// This is a critical error worth showing to the user
-				errorMsg := fmt.Sprintf("[%s] %s: %v", file.Domain, file.RelativePath, err)
-				e.summary.CriticalErrors = append(e.summary.CriticalErrors, errorMsg)
-				e.config.Logger.Warn("Failed to extract file",
-					"domain", file.Domain,
-					"path", file.RelativePath,
-					"file_id", file.FileID,
-					"error", err)
+			"total_files", len(files),
+			"workers", e.config.Workers)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	processed := 0
+	for w := 0; w < e.config.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				e.processFile(&files[i], journal, &processed, len(files))
 			}
-			continue
-		}
+		}()
 	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
 	e.config.Logger.Info("Backup extraction completed",
 		"total_files", e.summary.TotalFiles,
@@ -189,57 +235,248 @@ func (e *Extractor) Extract() (*ExtractSummary, error) {
 	return &e.summary, nil
 }
 
-// extractFile extracts a single file from the backup
-func (e *Extractor) extractFile(file *FileRecord) error {
-	// Skip files that aren't regular files (flags != 1)
-	if file.Flags != 1 {
-		e.summary.SkippedFiles++
-		return nil
+// fileFilter builds the manifest filter from the configured domains and path globs
+func (e *Extractor) fileFilter() FileFilter {
+	filter := FileFilter{
+		Domains: e.config.Domains,
+		Include: e.config.Include,
+		Exclude: e.config.Exclude,
+	}
+	if e.config.MediaOnly {
+		filter = filter.Merge(MediaOnlyFilter)
 	}
+	return filter
+}
 
-	// Build source path (hashed file in backup)
-	sourcePath := e.manifest.getActualFilePath(e.config.BackupPath, file.FileID)
+// estimateSize sums the sizes of the regular files about to be extracted, using the size
+// recorded in Manifest.db and falling back to the size of the backup file
+func (e *Extractor) estimateSize(files []FileRecord) int64 {
+	var total int64
+	for i := range files {
+		file := &files[i]
+		if file.Flags != 1 {
+			continue
+		}
+		if info, err := decodeFileInfo(file.File); err == nil && info.Size >= 0 {
+			total += info.Size
+			continue
+		}
+		if stat, err := os.Stat(e.manifest.getActualFilePath(e.config.BackupPath, file.FileID)); err == nil {
+			total += stat.Size()
+		}
+	}
+	return total
+}
 
-	// Check if source file exists
-	sourceInfo, err := os.Stat(sourcePath)
-	if err != nil {
-		return fmt.Errorf("source file not found: %w", err)
+// processFile extracts one file on a worker and records the outcome in the summary and journal
+func (e *Extractor) processFile(file *FileRecord, journal *extractionJournal, processed *int, total int) {
+	// Debug log for individual file processing (only visible with --log-level debug)
+	e.config.Logger.Debug("Processing file",
+		"domain", file.Domain,
+		"relative_path", file.RelativePath,
+		"file_id", file.FileID)
+
+	var written int64
+	var skipped, resumed bool
+	var err error
+	if journal.Done(file.FileID) {
+		resumed = true
+	} else {
+		written, skipped, err = e.extractFile(file)
+		if err == nil && !skipped {
+			err = journal.Record(file.FileID)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	*processed++
+	if e.config.Progress && (*processed%50 == 0 || *processed == total) {
+		progress := float64(*processed) / float64(total) * 100
+		e.config.Logger.Info("Extracting files",
+			"progress", fmt.Sprintf("%.1f%%", progress),
+			"processed", fmt.Sprintf("%d/%d", *processed, total),
+			"current_domain", file.Domain,
+			"current_file", filepath.Base(file.RelativePath))
+	}
+
+	// Record renamed files whether they were written now or by an earlier run
+	if err == nil && file.Flags == 1 {
+		if outputPath, escaped := extractedPath(file); escaped {
+			if e.summary.EscapedPaths == nil {
+				e.summary.EscapedPaths = make(map[string]string)
+			}
+			e.summary.EscapedPaths[path.Join(file.Domain, file.RelativePath)] = outputPath
+		}
 	}
 
-	// Build target path (reconstructed path)
-	targetPath := filepath.Join(e.config.OutputPath, file.Domain, file.RelativePath)
+	switch {
+	case err != nil:
+		e.summary.FailedFiles++
+
+		// Categorize the error
+		if isFilesystemCompatibilityError(err) {
+			e.summary.FilesystemCompatErrors++
+			// Only log filesystem compatibility errors at debug level
+			e.config.Logger.Debug("Skipped file due to filesystem compatibility",
+				"domain", file.Domain,
+				"path", file.RelativePath,
+				"error", err)
+		} else {
+			// This is a critical error worth showing to the user
+			errorMsg := fmt.Sprintf("[%s] %s: %v", file.Domain, file.RelativePath, err)
+			e.summary.CriticalErrors = append(e.summary.CriticalErrors, errorMsg)
+			e.config.Logger.Warn("Failed to extract file",
+				"domain", file.Domain,
+				"path", file.RelativePath,
+				"file_id", file.FileID,
+				"error", err)
+		}
+	case resumed:
+		e.summary.SkippedFiles++
+		e.summary.ResumedFiles++
+	case skipped:
+		e.summary.SkippedFiles++
+	default:
+		e.summary.ExtractedFiles++
+		e.summary.TotalSize += written
+		e.summary.ExtractedSize += written
+	}
+}
+
+// extractFile extracts a single file from the backup. It returns the number of bytes written,
+// or skipped=true when the file was left alone.
+func (e *Extractor) extractFile(file *FileRecord) (written int64, skipped bool, err error) {
+	// Skip files that aren't regular files (flags != 1)
+	if file.Flags != 1 {
+		return 0, true, nil
+	}
+
+	// Build target path (reconstructed path, with names the filesystem could reject escaped)
+	outputPath, _ := extractedPath(file)
+	targetPath := filepath.Join(e.config.OutputPath, filepath.FromSlash(outputPath))
 
 	// Skip if target exists and SkipExisting is enabled
 	if e.config.SkipExisting {
 		if _, err := os.Stat(targetPath); err == nil {
-			e.summary.SkippedFiles++
-			return nil
+			return 0, true, nil
 		}
 	}
 
 	// Create target directory
 	targetDir := filepath.Dir(targetPath)
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return fmt.Errorf("failed to create target directory: %w", err)
+		return 0, false, fmt.Errorf("failed to create target directory: %w", err)
+	}
+
+	// Decode the archived file metadata (size, mode, timestamps, keys)
+	info, err := decodeFileInfo(file.File)
+	if err != nil {
+		if e.encrypted != nil {
+			return 0, false, fmt.Errorf("failed to read file metadata: %w", err)
+		}
+		// Unencrypted files can still be copied without it
+		e.config.Logger.Debug("File metadata unavailable",
+			"domain", file.Domain,
+			"path", file.RelativePath,
+			"error", err)
+		info = nil
+	}
+
+	// Write to a temporary file next to the target and rename it into place once complete,
+	// so an interrupted extraction never leaves a partially written file under the real name.
+	// The temporary name is fixed so a resumed run overwrites leftovers instead of adding more.
+	tempPath := targetPath + partialSuffix
+	defer os.Remove(tempPath) // no-op once renamed
+
+	if e.encrypted != nil {
+		written, err = e.extractEncryptedFile(file, info, tempPath)
+	} else {
+		written, err = e.extractPlainFile(file, info, tempPath)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Restore the recorded permissions and modification time
+	if info != nil {
+		if err := applyFileInfo(tempPath, info); err != nil {
+			e.config.Logger.Debug("Could not restore file metadata",
+				"path", targetPath,
+				"error", err)
+		}
+	}
+
+	if err := os.Rename(tempPath, targetPath); err != nil {
+		return 0, false, fmt.Errorf("failed to move file into place: %w", err)
+	}
+
+	return written, false, nil
+}
+
+// extractedPath returns the slash-separated path of a file under the output directory,
+// Domain/relativePath, escaped when the name could be rejected by the filesystem
+func extractedPath(file *FileRecord) (string, bool) {
+	return escapePath(path.Join(file.Domain, file.RelativePath))
+}
+
+// extractPlainFile copies a single file of an unencrypted backup to targetPath
+func (e *Extractor) extractPlainFile(file *FileRecord, info *fileInfo, targetPath string) (int64, error) {
+	// Build source path (hashed file in backup)
+	sourcePath := e.manifest.getActualFilePath(e.config.BackupPath, file.FileID)
+
+	// Check if source file exists
+	sourceInfo, err := os.Stat(sourcePath)
+	if err != nil {
+		return 0, fmt.Errorf("source file not found: %w", err)
+	}
+
+	// A blob smaller than the recorded size was cut short while the backup was written
+	if info != nil && info.Size >= 0 && sourceInfo.Size() != info.Size {
+		return 0, fmt.Errorf("source file is %d bytes but Manifest.db records %d (truncated or incomplete backup)", sourceInfo.Size(), info.Size)
 	}
 
 	// Copy file
 	if err := e.copyFile(sourcePath, targetPath); err != nil {
-		return fmt.Errorf("failed to copy file: %w", err)
+		return 0, fmt.Errorf("failed to copy file: %w", err)
 	}
 
 	// Verify if requested
 	if e.config.Verify {
 		if err := e.verifyFile(sourcePath, targetPath); err != nil {
-			return fmt.Errorf("file verification failed: %w", err)
+			return 0, fmt.Errorf("file verification failed: %w", err)
+		}
+		if err := e.verifyDigest(targetPath, info); err != nil {
+			return 0, fmt.Errorf("file verification failed: %w", err)
 		}
 	}
 
-	e.summary.ExtractedFiles++
-	e.summary.TotalSize += sourceInfo.Size()
-	e.summary.ExtractedSize += sourceInfo.Size()
+	return sourceInfo.Size(), nil
+}
 
-	return nil
+// extractEncryptedFile decrypts a single file of an encrypted backup to targetPath
+func (e *Extractor) extractEncryptedFile(file *FileRecord, info *fileInfo, targetPath string) (int64, error) {
+	written, err := e.encrypted.decryptToFile(file, info, targetPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt file: %w", err)
+	}
+
+	// The ciphertext can't be hashed against the output, so verify the decrypted size instead
+	if e.config.Verify {
+		targetInfo, err := os.Stat(targetPath)
+		if err != nil {
+			return 0, fmt.Errorf("file verification failed: %w", err)
+		}
+		if targetInfo.Size() != written {
+			return 0, fmt.Errorf("file verification failed: wrote %d bytes, found %d", written, targetInfo.Size())
+		}
+		if err := e.verifyDigest(targetPath, info); err != nil {
+			return 0, fmt.Errorf("file verification failed: %w", err)
+		}
+	}
+
+	return written, nil
 }
 
 // copyFile copies a file from source to target
@@ -254,9 +491,12 @@ func (e *Extractor) copyFile(sourcePath, targetPath string) error {
 	if err != nil {
 		return err
 	}
-	defer targetFile.Close()
 
+	// A failed flush on close means the copy is incomplete
 	_, err = io.Copy(targetFile, sourceFile)
+	if closeErr := targetFile.Close(); err == nil {
+		err = closeErr
+	}
 	return err
 }
 
@@ -279,6 +519,24 @@ func (e *Extractor) verifyFile(sourcePath, targetPath string) error {
 	return nil
 }
 
+// verifyDigest checks an extracted file against the SHA-1 digest the device recorded in Manifest.db.
+// Files without a recorded digest pass.
+func (e *Extractor) verifyDigest(targetPath string, info *fileInfo) error {
+	if info == nil || len(info.Digest) != sha1.Size {
+		return nil
+	}
+
+	targetHash, err := e.calculateSHA1(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to calculate target hash: %w", err)
+	}
+	if recorded := fmt.Sprintf("%x", info.Digest); targetHash != recorded {
+		return fmt.Errorf("file hash doesn't match the digest recorded by the device (recorded: %s, target: %s)", recorded, targetHash)
+	}
+
+	return nil
+}
+
 // calculateSHA1 calculates SHA1 hash of a file
 func (e *Extractor) calculateSHA1(filePath string) (string, error) {
 	file, err := os.Open(filePath)
@@ -295,54 +553,35 @@ func (e *Extractor) calculateSHA1(filePath string) (string, error) {
 	return fmt.Sprintf("%x", hash.Sum(nil)), nil
 }
 
-// isBackupEncrypted checks if the backup is encrypted by examining Manifest.plist
+// isBackupEncrypted checks if the backup is encrypted by reading IsEncrypted from Manifest.plist
 func isBackupEncrypted(backupPath string) (bool, error) {
-	manifestPlistPath := filepath.Join(backupPath, "Manifest.plist")
-
-	// Read Manifest.plist content
-	content, err := os.ReadFile(manifestPlistPath)
+	manifestPlist, err := readManifestPlist(backupPath)
 	if err != nil {
-		return false, fmt.Errorf("failed to read Manifest.plist: %w", err)
-	}
-
-	// Simple check for encryption indicators in the plist
-	contentStr := strings.ToLower(string(content))
-
-	// Look for encryption-related keys
-	encryptionIndicators := []string{
-		"<key>isencrypted</key>",
-		"<key>encryption",
-		"<true/>", // If IsEncrypted key is followed by true
-	}
-
-	for _, indicator := range encryptionIndicators {
-		if strings.Contains(contentStr, indicator) {
-			// Additional check: if we see IsEncrypted key, check if it's followed by true
-			if strings.Contains(indicator, "isencrypted") {
-				// Find position of IsEncrypted key
-				pos := strings.Index(contentStr, indicator)
-				if pos >= 0 {
-					// Look for <true/> or <false/> after the key
-					afterKey := contentStr[pos+len(indicator):]
-					// Check next 100 chars or whatever is left
-					checkLength := 100
-					if len(afterKey) < checkLength {
-						checkLength = len(afterKey)
-					}
-					if strings.Contains(afterKey[:checkLength], "<true/>") {
-						return true, nil
-					}
-				}
-			}
-		}
+		return false, err
 	}
 
-	return false, nil
+	encrypted, _ := manifestPlist["IsEncrypted"].(bool)
+	return encrypted, nil
+}
+
+// humanizeBytes converts bytes to human readable format
+func humanizeBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-// isFilesystemCompatibilityError checks if an error is due to filesystem limitations
-// These are common on Windows due to invalid characters in filenames
-func isFilesystemCompatibilityError(err error, relativePath string) bool {
+// isFilesystemCompatibilityError checks if an error is due to filesystem limitations.
+// Names with characters the filesystem rejects are escaped before writing (see escapePath),
+// so only the errors a filesystem reports for other unsupported names are matched.
+func isFilesystemCompatibilityError(err error) bool {
 	if err == nil {
 		return false
 	}
@@ -350,31 +589,9 @@ func isFilesystemCompatibilityError(err error, relativePath string) bool {
 	errStr := strings.ToLower(err.Error())
 
 	// Windows-specific errors
-	if strings.Contains(errStr, "the filename, directory name, or volume label syntax is incorrect") ||
+	return strings.Contains(errStr, "the filename, directory name, or volume label syntax is incorrect") ||
 		strings.Contains(errStr, "invalid character") ||
-		strings.Contains(errStr, "bad file descriptor") {
-		return true
-	}
-
-	// Check for problematic characters in the path that are invalid on Windows
-	invalidChars := []string{":", "*", "?", "\"", "<", ">", "|"}
-	for _, char := range invalidChars {
-		if strings.Contains(relativePath, char) {
-			return true
-		}
-	}
-
-	// Check for URL-like paths (common cause of errors)
-	if strings.HasPrefix(relativePath, "http") || strings.Contains(relativePath, "://") {
-		return true
-	}
-
-	// Check for bundle identifier patterns (contain colons)
-	if strings.Contains(relativePath, ".plist") && strings.Count(relativePath, ":") > 1 {
-		return true
-	}
-
-	return false
+		strings.Contains(errStr, "bad file descriptor")
 }
 
 // validateManifestSchema validates that the Manifest.db has the expected schema
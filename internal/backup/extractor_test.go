@@ -1,13 +1,17 @@
 package backup
 
 import (
+	"database/sql"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/grantbirki/gh-photos/internal/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsBackupEncrypted(t *testing.T) {
@@ -130,7 +134,7 @@ func TestNewExtractor(t *testing.T) {
 			shouldError: false,
 		},
 		{
-			name: "encrypted_backup_should_fail",
+			name: "encrypted_backup_without_password_should_fail",
 			setupFunc: func(tempDir string) ExtractConfig {
 				// Create encrypted backup
 				os.WriteFile(filepath.Join(tempDir, "Manifest.plist"),
@@ -154,7 +158,7 @@ func TestNewExtractor(t *testing.T) {
 				}
 			},
 			shouldError: true,
-			errorMsg:    "encrypted backups are not supported",
+			errorMsg:    "no password was provided",
 		},
 		{
 			name: "missing_manifest",
@@ -249,3 +253,195 @@ func TestValidateManifestSchema(t *testing.T) {
 	_, err = CreateExtractor(config)
 	assert.Error(t, err) // Should fail because no valid backup exists
 }
+
+// createMultiFileFixture writes an unencrypted backup holding count small photos
+func createMultiFileFixture(t *testing.T, count int) string {
+	t.Helper()
+	backupDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, "Manifest.plist"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0"><dict><key>IsEncrypted</key><false/></dict></plist>`), 0644))
+
+	db, err := sql.Open("sqlite", filepath.Join(backupDir, "Manifest.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE Files (fileID TEXT PRIMARY KEY, domain TEXT, relativePath TEXT, flags INTEGER, file BLOB)`)
+	require.NoError(t, err)
+
+	for i := 0; i < count; i++ {
+		fileID := fmt.Sprintf("%02x%038d", i, i)
+		content := []byte(fmt.Sprintf("photo %d", i))
+		blobPath := filepath.Join(backupDir, fileID[:2], fileID)
+		require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0755))
+		require.NoError(t, os.WriteFile(blobPath, content, 0644))
+
+		_, err = db.Exec(`INSERT INTO Files VALUES (?, 'CameraRollDomain', ?, 1, ?)`,
+			fileID, fmt.Sprintf("Media/DCIM/100APPLE/IMG_%04d.JPG", i), plainMBFileArchive(len(content), 0o100644, 1700000000, nil))
+		require.NoError(t, err)
+	}
+
+	return backupDir
+}
+
+func TestExtractParallelAndResume(t *testing.T) {
+	backupDir := createMultiFileFixture(t, 25)
+	outputDir := t.TempDir()
+	log := logger.New(logger.Config{Level: logger.LevelInfo, Output: io.Discard})
+
+	extract := func(resume bool) *ExtractSummary {
+		extractor, err := CreateExtractor(ExtractConfig{
+			BackupPath: backupDir,
+			OutputPath: outputDir,
+			Workers:    4,
+			Resume:     resume,
+			Logger:     log,
+		})
+		require.NoError(t, err)
+		defer extractor.Close()
+
+		summary, err := extractor.Extract()
+		require.NoError(t, err)
+		return summary
+	}
+
+	summary := extract(false)
+	assert.Equal(t, 25, summary.ExtractedFiles)
+	assert.Equal(t, 0, summary.FailedFiles)
+
+	journal, err := os.ReadFile(filepath.Join(outputDir, "extraction-journal.log"))
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(journal)), "\n"), 25)
+
+	partials, err := filepath.Glob(filepath.Join(outputDir, "CameraRollDomain", "Media", "DCIM", "100APPLE", "*"+partialSuffix))
+	require.NoError(t, err)
+	assert.Empty(t, partials, "temporary files should be renamed into place")
+
+	// Simulate a crash after the first 10 files: truncate the journal to them
+	lines := strings.Split(strings.TrimSpace(string(journal)), "\n")
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "extraction-journal.log"),
+		[]byte(strings.Join(lines[:10], "\n")+"\n"+lines[10][:5]), 0644))
+
+	summary = extract(true)
+	assert.Equal(t, 10, summary.ResumedFiles)
+	assert.Equal(t, 15, summary.ExtractedFiles)
+
+	// The partial line was terminated, so a second resume finds every file completed
+	summary = extract(true)
+	assert.Equal(t, 25, summary.ResumedFiles)
+	assert.Equal(t, 0, summary.ExtractedFiles)
+
+	// Without --resume the journal is started over and every file is extracted again
+	summary = extract(false)
+	assert.Equal(t, 0, summary.ResumedFiles)
+	assert.Equal(t, 25, summary.ExtractedFiles)
+}
+
+func TestExtractFilters(t *testing.T) {
+	backupDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, "Manifest.plist"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0"><dict><key>IsEncrypted</key><false/></dict></plist>`), 0644))
+
+	db, err := sql.Open("sqlite", filepath.Join(backupDir, "Manifest.db"))
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE Files (fileID TEXT PRIMARY KEY, domain TEXT, relativePath TEXT, flags INTEGER, file BLOB)`)
+	require.NoError(t, err)
+
+	files := []struct{ domain, path, content string }{
+		{"CameraRollDomain", "Media/DCIM/100APPLE/IMG_0001.JPG", "photo one"},
+		{"CameraRollDomain", "Media/DCIM/100APPLE/IMG_0001.AAE", "edits"},
+		{"CameraRollDomain", "Media/PhotoData/Photos.sqlite", "database"},
+		{"HomeDomain", "Library/Preferences/com.apple.springboard.plist", "prefs"},
+		{"AppDomain-com.example.app", "Documents/notes.txt", "notes"},
+	}
+	for i, file := range files {
+		fileID := fmt.Sprintf("%02x%038d", i, i)
+		blobPath := filepath.Join(backupDir, fileID[:2], fileID)
+		require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0755))
+		require.NoError(t, os.WriteFile(blobPath, []byte(file.content), 0644))
+		_, err = db.Exec(`INSERT INTO Files VALUES (?, ?, ?, 1, ?)`, fileID, file.domain, file.path,
+			plainMBFileArchive(len(file.content), 0o100644, 1700000000, nil))
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	tests := []struct {
+		name     string
+		config   ExtractConfig
+		expected []string
+	}{
+		{
+			name:   "media only",
+			config: ExtractConfig{MediaOnly: true},
+			expected: []string{
+				"CameraRollDomain/Media/DCIM/100APPLE/IMG_0001.AAE",
+				"CameraRollDomain/Media/DCIM/100APPLE/IMG_0001.JPG",
+				"CameraRollDomain/Media/PhotoData/Photos.sqlite",
+			},
+		},
+		{
+			name:   "media only with exclude",
+			config: ExtractConfig{MediaOnly: true, Exclude: []string{"*.AAE"}},
+			expected: []string{
+				"CameraRollDomain/Media/DCIM/100APPLE/IMG_0001.JPG",
+				"CameraRollDomain/Media/PhotoData/Photos.sqlite",
+			},
+		},
+		{
+			name:     "domain glob",
+			config:   ExtractConfig{Domains: []string{"AppDomain-*"}},
+			expected: []string{"AppDomain-com.example.app/Documents/notes.txt"},
+		},
+		{
+			name:     "include glob spans directories",
+			config:   ExtractConfig{Include: []string{"Library/*.plist"}},
+			expected: []string{"HomeDomain/Library/Preferences/com.apple.springboard.plist"},
+		},
+		{
+			name:     "globs are case-sensitive",
+			config:   ExtractConfig{Include: []string{"*.jpg"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			config := tt.config
+			config.BackupPath = backupDir
+			config.OutputPath = outputDir
+			config.Logger = logger.New(logger.Config{Level: logger.LevelInfo, Output: io.Discard})
+
+			extractor, err := CreateExtractor(config)
+			require.NoError(t, err)
+			defer extractor.Close()
+
+			summary, err := extractor.Extract()
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.expected), summary.TotalFiles)
+
+			// Every extracted file sits under a domain directory; the root only holds bookkeeping files
+			var extracted []string
+			var estimated int64
+			err = filepath.WalkDir(outputDir, func(path string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() || filepath.Dir(path) == outputDir {
+					return err
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(outputDir, path)
+				if err != nil {
+					return err
+				}
+				extracted = append(extracted, filepath.ToSlash(rel))
+				estimated += info.Size()
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, extracted)
+			assert.Equal(t, estimated, summary.EstimatedSize)
+			assert.Equal(t, summary.EstimatedSize, summary.ExtractedSize)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/grantbirki/gh-photos/internal/types"
+)
+
+// hashedMedia indexes the camera roll files recorded in Manifest.db so the assets of a hashed
+// (un-extracted) backup can be read straight from their backup files. The index is built with
+// a single query over the Files table.
+type hashedMedia struct {
+	backupPath string
+	records    map[string]*FileRecord // keyed by relativePath, e.g. Media/DCIM/100APPLE/IMG_0001.HEIC
+	dirs       map[string][]string    // file names keyed by the relativePath of their directory
+	encrypted  *EncryptedBackup       // set for encrypted backups, whose files are decrypted before upload
+	staged     map[string]*stagedFile // files of an encrypted backup, keyed by the path they decrypt to
+}
+
+// stagedFile is a file of an encrypted backup together with its decoded metadata
+type stagedFile struct {
+	record    *FileRecord
+	info      *fileInfo
+	decrypted bool // whether the file is currently decrypted on disk
+}
+
+// openHashedMedia indexes the media files of an unencrypted hashed backup
+func openHashedMedia(backupPath string) (*hashedMedia, error) {
+	manifestDB, err := OpenManifestDB(backupPath)
+	if err != nil {
+		return nil, err
+	}
+	defer manifestDB.Close()
+
+	return loadHashedMedia(backupPath, manifestDB)
+}
+
+// loadHashedMedia indexes the camera roll and PhotoData files recorded in manifestDB
+func loadHashedMedia(backupPath string, manifestDB *ManifestDB) (*hashedMedia, error) {
+	files, err := manifestDB.GetFiles(MediaOnlyFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to index media files: %w", err)
+	}
+
+	media := &hashedMedia{
+		backupPath: backupPath,
+		records:    make(map[string]*FileRecord, len(files)),
+		dirs:       make(map[string][]string),
+		staged:     make(map[string]*stagedFile),
+	}
+	for i := range files {
+		file := &files[i]
+		if file.Flags != 1 {
+			continue // directories and symlinks
+		}
+		// Files are ordered by domain, so CameraRollDomain wins over MediaDomain for the same path
+		if _, ok := media.records[file.RelativePath]; ok {
+			continue
+		}
+		media.records[file.RelativePath] = file
+		dir := path.Dir(file.RelativePath)
+		media.dirs[dir] = append(media.dirs[dir], path.Base(file.RelativePath))
+	}
+	return media, nil
+}
+
+// find returns the record of a path relative to the media root, such as DCIM/100APPLE/IMG_0001.HEIC.
+// iOS records camera roll files under Media/ in CameraRollDomain.
+func (m *hashedMedia) find(devicePath string) *FileRecord {
+	for _, candidate := range []string{path.Join("Media", devicePath), devicePath} {
+		if record, ok := m.records[candidate]; ok {
+			return record
+		}
+	}
+	return nil
+}
+
+// stat returns the local path a file is read from and its size. That is the backup file itself,
+// or for encrypted backups the path in the private temp directory it is decrypted to by prepare.
+// The metadata of an encrypted file is decoded once and kept until the parser is closed.
+func (m *hashedMedia) stat(record *FileRecord) (string, int64, error) {
+	blobPath := backupFilePath(m.backupPath, record.FileID)
+	stat, err := os.Stat(blobPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("source file not found: %w", err)
+	}
+	if m.encrypted == nil {
+		return blobPath, stat.Size(), nil
+	}
+
+	// Decrypted files keep their device path, so they also keep their file extension
+	stagedPath := filepath.Join(m.encrypted.TempDir(), "Media", record.Domain, filepath.FromSlash(record.RelativePath))
+	if staged, ok := m.staged[stagedPath]; ok {
+		return stagedPath, staged.info.Size, nil
+	}
+
+	info, err := decodeFileInfo(record.File)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read file metadata: %w", err)
+	}
+	if info.Size < 0 {
+		return "", 0, fmt.Errorf("file size not recorded in Manifest.db")
+	}
+
+	m.staged[stagedPath] = &stagedFile{record: record, info: info}
+	return stagedPath, info.Size, nil
+}
+
+// prepare decrypts the staged files among paths. Paths that aren't staged, or are already
+// decrypted, are left alone.
+func (m *hashedMedia) prepare(paths []string) error {
+	for _, stagedPath := range paths {
+		staged, ok := m.staged[stagedPath]
+		if !ok || staged.decrypted {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(stagedPath), 0700); err != nil {
+			return fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		if _, err := m.encrypted.decryptToFile(staged.record, staged.info, stagedPath); err != nil {
+			os.Remove(stagedPath)
+			return fmt.Errorf("failed to decrypt %s: %w", staged.record.RelativePath, err)
+		}
+		staged.decrypted = true
+	}
+	return nil
+}
+
+// release deletes the decrypted copies of the staged files among paths. They can be decrypted
+// again by prepare.
+func (m *hashedMedia) release(paths []string) error {
+	for _, stagedPath := range paths {
+		staged, ok := m.staged[stagedPath]
+		if !ok || !staged.decrypted {
+			continue
+		}
+		if err := os.Remove(stagedPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove decrypted file: %w", err)
+		}
+		staged.decrypted = false
+	}
+	return nil
+}
+
+// enrichHashedAsset resolves an asset of a hashed backup to its backup file through the
+// Manifest.db index. The asset keeps its device filename, which is used for the remote path.
+func (bp *BackupParser) enrichHashedAsset(asset *types.Asset) error {
+	root, err := filepath.Abs(bp.dcimPath)
+	if err != nil {
+		return err
+	}
+	devicePath, err := filepath.Rel(root, asset.SourcePath)
+	if err != nil {
+		return fmt.Errorf("source file not found: %w", err)
+	}
+
+	record := bp.media.find(filepath.ToSlash(devicePath))
+	if record == nil {
+		return fmt.Errorf("source file not found: %s is not recorded in Manifest.db", filepath.ToSlash(devicePath))
+	}
+
+	sourcePath, size, err := bp.media.stat(record)
+	if err != nil {
+		return err
+	}
+	asset.SourcePath = sourcePath
+	asset.DevicePath = record.RelativePath
+	asset.FileSize = size
+	asset.MimeType = inferMimeType(asset.Filename)
+
+	if asset.Type == types.AssetTypeLivePhoto {
+		bp.resolveHashedLivePhotoVideo(asset, record)
+	}
+	if asset.Flags.Edited {
+		bp.resolveHashedEditedRender(asset, record)
+	}
+	return nil
+}
+
+// resolveHashedLivePhotoVideo finds the companion video of a Live Photo still in the Manifest.db index
+func (bp *BackupParser) resolveHashedLivePhotoVideo(asset *types.Asset, still *FileRecord) {
+	dir := path.Dir(still.RelativePath)
+	for _, candidate := range types.LivePhotoVideoCandidates(path.Base(still.RelativePath)) {
+		record, ok := bp.media.records[path.Join(dir, candidate)]
+		if !ok {
+			continue
+		}
+		videoPath, size, err := bp.media.stat(record)
+		if err != nil {
+			continue
+		}
+
+		videoID := candidate
+		asset.LivePhotoVideoPath = videoPath
+		asset.LivePhotoVideoSize = size
+		asset.Flags.LivePhotoVideoID = &videoID
+		return
+	}
+
+	bp.logger.Debugf("No paired video found for Live Photo %s", asset.Filename)
+}
+
+// resolveHashedEditedRender finds the FullSizeRender of an edited asset in the Manifest.db index
+func (bp *BackupParser) resolveHashedEditedRender(asset *types.Asset, original *FileRecord) {
+	// editedRenderDir expects a path containing /DCIM/
+	adjustmentsDir := editedRenderDir("/" + original.RelativePath)
+	if adjustmentsDir == "" {
+		return
+	}
+	adjustmentsDir = strings.TrimPrefix(filepath.ToSlash(adjustmentsDir), "/")
+
+	// Prefer a render of the same kind as the original (Live Photos also get a FullSizeRender.mov)
+	wantVideo := asset.Type.IsVideo()
+	for _, name := range bp.media.dirs[adjustmentsDir] {
+		if !strings.HasPrefix(name, "FullSizeRender.") {
+			continue
+		}
+		if (types.ClassifyByExtension(name) == types.AssetTypeVideo) != wantVideo {
+			continue
+		}
+		renderPath, size, err := bp.media.stat(bp.media.records[path.Join(adjustmentsDir, name)])
+		if err != nil {
+			continue
+		}
+
+		asset.EditedPath = renderPath
+		asset.EditedFilename = name
+		asset.EditedSize = size
+		asset.EditedMimeType = inferMimeType(name)
+		return
+	}
+
+	bp.logger.Debugf("No edited render found for %s", asset.Filename)
+}
@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantbirki/gh-photos/internal/logger"
+	"github.com/grantbirki/gh-photos/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createHashedFixture writes an unencrypted hashed backup holding the given relativePath -> content
+// files in CameraRollDomain and returns the backup directory
+func createHashedFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	backupDir := t.TempDir()
+
+	db, err := sql.Open("sqlite", filepath.Join(backupDir, "Manifest.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE Files (fileID TEXT PRIMARY KEY, domain TEXT, relativePath TEXT, flags INTEGER, file BLOB)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO Files VALUES ('dir0000000000000000000000000000000000000', 'CameraRollDomain', 'Media/DCIM', 2, NULL)`)
+	require.NoError(t, err)
+
+	i := 0
+	for relativePath, content := range files {
+		fileID := fmt.Sprintf("%02x%038d", i, i)
+		blobPath := filepath.Join(backupDir, fileID[:2], fileID)
+		require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0755))
+		require.NoError(t, os.WriteFile(blobPath, []byte(content), 0644))
+
+		_, err = db.Exec(`INSERT INTO Files VALUES (?, 'CameraRollDomain', ?, 1, ?)`,
+			fileID, relativePath, plainMBFileArchive(len(content), 0o100644, 1700000000, nil))
+		require.NoError(t, err)
+		i++
+	}
+	return backupDir
+}
+
+func TestEnrichHashedAsset(t *testing.T) {
+	backupDir := createHashedFixture(t, map[string]string{
+		"Media/DCIM/100APPLE/IMG_0001.HEIC":                                               "still",
+		"Media/DCIM/100APPLE/IMG_0001.MOV":                                                "motion",
+		"Media/PhotoData/Mutations/DCIM/100APPLE/IMG_0001/Adjustments/FullSizeRender.jpg": "render",
+	})
+	media, err := openHashedMedia(backupDir)
+	require.NoError(t, err)
+
+	bp := &BackupParser{
+		dcimPath: backupDir,
+		media:    media,
+		logger:   logger.New(logger.Config{Level: logger.LevelError}),
+	}
+
+	// Photos.sqlite records ZDIRECTORY relative to the media root
+	sourcePath, err := filepath.Abs(filepath.Join(backupDir, "DCIM", "100APPLE", "IMG_0001.HEIC"))
+	require.NoError(t, err)
+	asset := &types.Asset{
+		SourcePath: sourcePath,
+		Filename:   "IMG_0001.HEIC",
+		Type:       types.AssetTypeLivePhoto,
+		Flags:      types.AssetFlags{Edited: true},
+	}
+	require.NoError(t, bp.enrichAsset(asset))
+
+	still := media.find("DCIM/100APPLE/IMG_0001.HEIC")
+	require.NotNil(t, still)
+	assert.Equal(t, backupFilePath(backupDir, still.FileID), asset.SourcePath)
+	assert.Equal(t, "Media/DCIM/100APPLE/IMG_0001.HEIC", asset.DevicePath)
+	assert.Equal(t, int64(len("still")), asset.FileSize)
+	assert.Equal(t, "image/heif", asset.MimeType)
+
+	// Companion files resolve to their own backup files but keep their device names for the remote
+	assert.Equal(t, int64(len("motion")), asset.LivePhotoVideoSize)
+	assert.Equal(t, "IMG_0001.MOV", asset.LivePhotoVideoFilename())
+	assert.Equal(t, "2024/01/02/IMG_0001.MOV", types.LivePhotoVideoTargetPath("2024/01/02/IMG_0001.HEIC", asset.LivePhotoVideoFilename()))
+	assert.Equal(t, int64(len("render")), asset.EditedSize)
+	assert.Equal(t, "FullSizeRender.jpg", asset.EditedRenderFilename())
+	assert.Equal(t, "image/jpeg", asset.EditedMimeType)
+
+	content, err := os.ReadFile(asset.EditedPath)
+	require.NoError(t, err)
+	assert.Equal(t, "render", string(content))
+
+	missing := &types.Asset{SourcePath: filepath.Join(filepath.Dir(sourcePath), "IMG_0002.HEIC"), Filename: "IMG_0002.HEIC"}
+	assert.ErrorContains(t, bp.enrichAsset(missing), "not recorded in Manifest.db")
+}
+
+func TestPrepareEncryptedAssets(t *testing.T) {
+	files := fixtureFiles()
+	backupDir := createEncryptedFixture(t, files)
+
+	eb, err := OpenEncryptedBackup(backupDir, fixturePassword)
+	require.NoError(t, err)
+	media, err := loadHashedMedia(backupDir, eb.Manifest())
+	require.NoError(t, err)
+	media.encrypted = eb
+
+	bp := &BackupParser{
+		dcimPath:  backupDir,
+		encrypted: eb,
+		media:     media,
+		logger:    logger.New(logger.Config{Level: logger.LevelError}),
+	}
+	defer bp.Close()
+
+	sourcePath, err := filepath.Abs(filepath.Join(backupDir, "DCIM", "100APPLE", "IMG_0001.JPG"))
+	require.NoError(t, err)
+	asset := &types.Asset{SourcePath: sourcePath, Filename: "IMG_0001.JPG", Type: types.AssetTypePhoto}
+	require.NoError(t, bp.enrichAsset(asset))
+
+	// Nothing is decrypted until the asset is prepared for upload
+	assert.Equal(t, int64(len(files[0].content)), asset.FileSize)
+	assert.Equal(t, "IMG_0001.JPG", filepath.Base(asset.SourcePath))
+	_, err = os.Stat(asset.SourcePath)
+	assert.True(t, os.IsNotExist(err))
+
+	paths := []string{asset.SourcePath}
+	require.NoError(t, bp.PrepareFiles(paths))
+	content, err := os.ReadFile(asset.SourcePath)
+	require.NoError(t, err)
+	assert.Equal(t, files[0].content, content)
+
+	// Released files are deleted and can be decrypted again
+	require.NoError(t, bp.ReleaseFiles(paths))
+	_, err = os.Stat(asset.SourcePath)
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, bp.PrepareFiles(paths))
+	content, err = os.ReadFile(asset.SourcePath)
+	require.NoError(t, err)
+	assert.Equal(t, files[0].content, content)
+}
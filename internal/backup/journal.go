@@ -0,0 +1,101 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// extractionJournal records the file IDs an extraction has finished, one per line, so an
+// interrupted extraction can be resumed. A file ID is only appended once the file has been
+// renamed into place, so every journaled file is complete.
+type extractionJournal struct {
+	mu        sync.Mutex
+	file      *os.File
+	completed map[string]bool
+}
+
+// openExtractionJournal opens the journal at path. When resume is set the file IDs already
+// recorded are loaded and new entries are appended; otherwise the journal starts empty.
+func openExtractionJournal(path string, resume bool) (*extractionJournal, error) {
+	journal := &extractionJournal{completed: make(map[string]bool)}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	partialLine := false
+	if resume {
+		var err error
+		if partialLine, err = journal.load(path); err != nil {
+			return nil, err
+		}
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open extraction journal: %w", err)
+	}
+	journal.file = file
+
+	// Terminate a partial line so the next entry starts on its own line
+	if partialLine {
+		if _, err := file.WriteString("\n"); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to write extraction journal: %w", err)
+		}
+	}
+	return journal, nil
+}
+
+// load reads the file IDs recorded by a previous run; a missing journal is treated as empty.
+// Returns whether the journal ends in a partial line, left behind by a crash mid-write.
+func (j *extractionJournal) load(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read extraction journal: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		// A partial last line never matches a real file ID
+		if fileID := strings.TrimSpace(line); fileID != "" {
+			j.completed[fileID] = true
+		}
+	}
+	return len(data) > 0 && data[len(data)-1] != '\n', nil
+}
+
+// Count returns how many file IDs have been recorded
+func (j *extractionJournal) Count() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.completed)
+}
+
+// Done reports whether fileID was completed by this or a previous run
+func (j *extractionJournal) Done(fileID string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.completed[fileID]
+}
+
+// Record marks fileID as completed
+func (j *extractionJournal) Record(fileID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.file.WriteString(fileID + "\n"); err != nil {
+		return fmt.Errorf("failed to write extraction journal: %w", err)
+	}
+	j.completed[fileID] = true
+	return nil
+}
+
+// Close closes the journal file
+func (j *extractionJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	_ "modernc.org/sqlite"
 )
@@ -33,6 +34,11 @@ func OpenManifestDB(backupPath string) (*ManifestDB, error) {
 		return nil, fmt.Errorf("Manifest.db not found at %s: %w", manifestPath, err)
 	}
 
+	return openManifestDBFile(manifestPath)
+}
+
+// openManifestDBFile opens a Manifest.db at an explicit path, such as a decrypted copy
+func openManifestDBFile(manifestPath string) (*ManifestDB, error) {
 	db, err := sql.Open("sqlite", manifestPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open Manifest.db: %w", err)
@@ -60,6 +66,24 @@ func (m *ManifestDB) Close() error {
 
 // FindPhotosDatabase searches for Photos.sqlite in the manifest
 func (m *ManifestDB) FindPhotosDatabase(backupPath string) (string, error) {
+	fileRecord, err := m.findPhotosDatabaseRecord()
+	if err != nil {
+		return "", err
+	}
+
+	// Convert fileID to actual file path
+	actualPath := m.getActualFilePath(backupPath, fileRecord.FileID)
+
+	// Verify the file exists
+	if _, err := os.Stat(actualPath); err != nil {
+		return "", fmt.Errorf("Photos.sqlite file not found at computed path %s: %w", actualPath, err)
+	}
+
+	return actualPath, nil
+}
+
+// findPhotosDatabaseRecord returns the manifest record of Photos.sqlite
+func (m *ManifestDB) findPhotosDatabaseRecord() (*FileRecord, error) {
 	// Query patterns to look for Photos.sqlite
 	patterns := []string{
 		"%Photos.sqlite",
@@ -97,18 +121,38 @@ func (m *ManifestDB) FindPhotosDatabase(backupPath string) (string, error) {
 	}
 
 	if fileRecord == nil {
-		return "", fmt.Errorf("Photos.sqlite not found in Manifest.db")
+		return nil, fmt.Errorf("Photos.sqlite not found in Manifest.db")
 	}
 
-	// Convert fileID to actual file path
-	actualPath := m.getActualFilePath(backupPath, fileRecord.FileID)
+	return fileRecord, nil
+}
 
-	// Verify the file exists
-	if _, err := os.Stat(actualPath); err != nil {
-		return "", fmt.Errorf("Photos.sqlite file not found at computed path %s: %w", actualPath, err)
+// findFile looks up a file by its exact domain and relative path
+func (m *ManifestDB) findFile(domain, relativePath string) (*FileRecord, error) {
+	query := `
+		SELECT fileID, domain, relativePath, flags, file 
+		FROM Files 
+		WHERE domain = ? AND relativePath = ?
+		LIMIT 1
+	`
+
+	var record FileRecord
+	err := m.db.QueryRow(query, domain, relativePath).Scan(
+		&record.FileID,
+		&record.Domain,
+		&record.RelativePath,
+		&record.Flags,
+		&record.File,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Files table: %w", err)
 	}
 
-	return actualPath, nil
+	return &record, nil
 }
 
 // findFileByPath searches for a file by relative path pattern
@@ -176,6 +220,11 @@ func (m *ManifestDB) findFileByDomainAndName(domain, filename string) (*FileReco
 
 // getActualFilePath converts a fileID to the actual hashed file path
 func (m *ManifestDB) getActualFilePath(backupPath, fileID string) string {
+	return backupFilePath(backupPath, fileID)
+}
+
+// backupFilePath returns the path of a hashed file in the backup directory
+func backupFilePath(backupPath, fileID string) string {
 	// iPhone backup files are stored in subdirectories based on first 2 chars of fileID
 	if len(fileID) < 2 {
 		return filepath.Join(backupPath, fileID)
@@ -282,17 +331,82 @@ func (m *ManifestDB) HasMediaFiles() (bool, error) {
 	return count > 0, nil
 }
 
+// FileFilter narrows the files returned by GetFiles. Patterns are SQLite GLOB patterns:
+// case-sensitive, and '*' also matches '/'. An empty list places no restriction.
+type FileFilter struct {
+	Domains []string // domain patterns; a file matches if its domain matches any of them
+	Include []string // relative path patterns; a file matches if its path matches any of them
+	Exclude []string // relative path patterns; a file is dropped if its path matches any of them
+}
+
+// MediaOnlyFilter covers the camera roll, the PhotoData directory and the Photos database
+var MediaOnlyFilter = FileFilter{
+	Domains: []string{"CameraRollDomain", "MediaDomain"},
+	Include: []string{"Media/DCIM/*", "Media/PhotoData/*", "DCIM/*", "PhotoData/*"},
+}
+
+// Merge returns a filter matching the union of both filters' domains and include patterns
+// and the exclude patterns of either
+func (f FileFilter) Merge(other FileFilter) FileFilter {
+	return FileFilter{
+		Domains: append(append([]string{}, f.Domains...), other.Domains...),
+		Include: append(append([]string{}, f.Include...), other.Include...),
+		Exclude: append(append([]string{}, f.Exclude...), other.Exclude...),
+	}
+}
+
+// IsEmpty reports whether the filter matches every file
+func (f FileFilter) IsEmpty() bool {
+	return len(f.Domains) == 0 && len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// whereClause builds the SQL condition and arguments for the filter
+func (f FileFilter) whereClause() (string, []any) {
+	var conditions []string
+	var args []any
+
+	anyOf := func(column string, patterns []string) {
+		if len(patterns) == 0 {
+			return
+		}
+		globs := make([]string, len(patterns))
+		for i, pattern := range patterns {
+			globs[i] = column + " GLOB ?"
+			args = append(args, pattern)
+		}
+		conditions = append(conditions, "("+strings.Join(globs, " OR ")+")")
+	}
+	anyOf("domain", f.Domains)
+	anyOf("relativePath", f.Include)
+	for _, pattern := range f.Exclude {
+		conditions = append(conditions, "relativePath NOT GLOB ?")
+		args = append(args, pattern)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
 // GetAllFiles returns all files from the manifest for extraction
 func (m *ManifestDB) GetAllFiles() ([]FileRecord, error) {
+	return m.GetFiles(FileFilter{})
+}
+
+// GetFiles returns the files matching filter, ordered by domain and path
+func (m *ManifestDB) GetFiles(filter FileFilter) ([]FileRecord, error) {
+	where, args := filter.whereClause()
 	query := `
 		SELECT fileID, domain, relativePath, flags, file 
 		FROM Files 
+		` + where + `
 		ORDER BY domain, relativePath
 	`
 
-	rows, err := m.db.Query(query)
+	rows, err := m.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query all files: %w", err)
+		return nil, fmt.Errorf("failed to query files: %w", err)
 	}
 	defer rows.Close()
 
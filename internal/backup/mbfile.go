@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantbirki/gh-photos/internal/plist"
+)
+
+// fileInfo holds the fields of a Manifest.db file blob (an NSKeyedArchiver-encoded MBFile)
+type fileInfo struct {
+	Size            int64 // -1 when the archive doesn't record a size
+	Mode            os.FileMode
+	LastModified    time.Time
+	Birth           time.Time // creation time; only applied when no modification time is recorded
+	Digest          []byte    // SHA-1 of the file contents recorded by the device, when present
+	ProtectionClass uint32
+	EncryptionKey   []byte // 4 byte protection class followed by the wrapped file key; encrypted backups only
+}
+
+// decodeFileInfo decodes the MBFile archive stored in the Files.file column
+func decodeFileInfo(blob []byte) (*fileInfo, error) {
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("file record has no metadata")
+	}
+
+	archive, err := plist.DecodeKeyedArchive(blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode file metadata: %w", err)
+	}
+
+	root := archive.Root()
+	info := &fileInfo{Size: -1}
+	if size, ok := archive.Int(root, "Size"); ok {
+		info.Size = size
+	}
+	if mode, ok := archive.Int(root, "Mode"); ok {
+		// Mode carries the file type bits as well; only the permissions apply to extracted files
+		info.Mode = os.FileMode(mode).Perm()
+	}
+	if modified, ok := archive.Int(root, "LastModified"); ok && modified > 0 {
+		info.LastModified = time.Unix(modified, 0)
+	}
+	if birth, ok := archive.Int(root, "Birth"); ok && birth > 0 {
+		info.Birth = time.Unix(birth, 0)
+	}
+	if digest, ok := archive.Data(root, "Digest"); ok {
+		info.Digest = digest
+	}
+	if class, ok := archive.Int(root, "ProtectionClass"); ok {
+		info.ProtectionClass = uint32(class)
+	}
+	if key, ok := archive.Data(root, "EncryptionKey"); ok {
+		info.EncryptionKey = key
+	}
+
+	return info, nil
+}
+
+// applyFileInfo restores the permissions and modification time recorded for an extracted file.
+// Creation times can't be set portably, so the birth time is used as the modification time of
+// files that don't record one.
+func applyFileInfo(path string, info *fileInfo) error {
+	if info.Mode != 0 {
+		// Keep the file readable and writable by its owner so it can be re-extracted over
+		if err := os.Chmod(path, info.Mode|0600); err != nil {
+			return fmt.Errorf("failed to set file mode: %w", err)
+		}
+	}
+	modified := info.LastModified
+	if modified.IsZero() {
+		modified = info.Birth
+	}
+	if !modified.IsZero() {
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			return fmt.Errorf("failed to set file times: %w", err)
+		}
+	}
+	return nil
+}
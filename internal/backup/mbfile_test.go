@@ -0,0 +1,184 @@
+package backup
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/grantbirki/gh-photos/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainMBFileArchive builds the keyed archive stored in Files.file for an unencrypted file, created
+// a minute before it was last modified
+func plainMBFileArchive(size int, mode int, modified int64, digest []byte) []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>$archiver</key><string>NSKeyedArchiver</string>
+	<key>$top</key><dict><key>root</key><dict><key>CF$UID</key><integer>1</integer></dict></dict>
+	<key>$objects</key>
+	<array>
+		<string>$null</string>
+		<dict>
+			<key>Size</key><integer>%d</integer>
+			<key>Mode</key><integer>%d</integer>
+			<key>LastModified</key><integer>%d</integer>
+			<key>Birth</key><integer>%d</integer>
+			<key>Digest</key><dict><key>CF$UID</key><integer>2</integer></dict>
+			<key>ProtectionClass</key><integer>0</integer>
+		</dict>
+		<data>%s</data>
+	</array>
+</dict>
+</plist>`, size, mode, modified, modified-60, base64.StdEncoding.EncodeToString(digest)))
+}
+
+func TestDecodeFileInfo(t *testing.T) {
+	info, err := decodeFileInfo(plainMBFileArchive(2048, 0o100640, 1700000000, []byte{0xDE, 0xAD, 0xBE, 0xEF}))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2048), info.Size)
+	assert.Equal(t, os.FileMode(0o640), info.Mode)
+	assert.Equal(t, time.Unix(1700000000, 0), info.LastModified)
+	assert.Equal(t, time.Unix(1699999940, 0), info.Birth)
+	assert.Equal(t, []byte{0xDE, 0xAD, 0xBE, 0xEF}, info.Digest)
+	assert.Empty(t, info.EncryptionKey)
+
+	_, err = decodeFileInfo(nil)
+	assert.Error(t, err)
+}
+
+func TestApplyFileInfoFallsBackToBirth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "IMG_0001.JPG")
+	require.NoError(t, os.WriteFile(path, []byte("jpeg"), 0644))
+
+	birth := time.Unix(1690000000, 0)
+	require.NoError(t, applyFileInfo(path, &fileInfo{Birth: birth}))
+	stat, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, stat.ModTime().Equal(birth))
+
+	// A recorded modification time wins
+	modified := time.Unix(1700000000, 0)
+	require.NoError(t, applyFileInfo(path, &fileInfo{LastModified: modified, Birth: birth}))
+	stat, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, stat.ModTime().Equal(modified))
+}
+
+// createPlainFixture writes an unencrypted backup whose single photo is recorded with recordedSize bytes
+// and the SHA-1 digest of content
+func createPlainFixture(t *testing.T, content []byte, recordedSize int, modified int64) (string, string) {
+	t.Helper()
+	backupDir := t.TempDir()
+	fileID := "ab00000000000000000000000000000000000001"
+	digest := sha1.Sum(content)
+
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, "Manifest.plist"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0"><dict><key>IsEncrypted</key><false/></dict></plist>`), 0644))
+
+	blobPath := filepath.Join(backupDir, fileID[:2], fileID)
+	require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0755))
+	require.NoError(t, os.WriteFile(blobPath, content, 0644))
+
+	db, err := sql.Open("sqlite", filepath.Join(backupDir, "Manifest.db"))
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE Files (fileID TEXT PRIMARY KEY, domain TEXT, relativePath TEXT, flags INTEGER, file BLOB)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO Files VALUES (?, 'CameraRollDomain', 'Media/DCIM/100APPLE/IMG_0002.JPG', 1, ?)`,
+		fileID, plainMBFileArchive(recordedSize, 0o100640, modified, digest[:]))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	return backupDir, filepath.Join("CameraRollDomain", "Media", "DCIM", "100APPLE", "IMG_0002.JPG")
+}
+
+func TestExtractRestoresFileMetadata(t *testing.T) {
+	content := []byte("jpeg bytes")
+	modified := int64(1700000000)
+	backupDir, relPath := createPlainFixture(t, content, len(content), modified)
+	outputDir := t.TempDir()
+
+	extractor, err := CreateExtractor(ExtractConfig{
+		BackupPath: backupDir,
+		OutputPath: outputDir,
+		Logger:     logger.New(logger.Config{Level: logger.LevelInfo, Output: io.Discard}),
+	})
+	require.NoError(t, err)
+	defer extractor.Close()
+
+	summary, err := extractor.Extract()
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.ExtractedFiles)
+
+	stat, err := os.Stat(filepath.Join(outputDir, relPath))
+	require.NoError(t, err)
+	assert.True(t, stat.ModTime().Equal(time.Unix(modified, 0)), "mtime %v", stat.ModTime())
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o640), stat.Mode().Perm())
+	}
+}
+
+func TestExtractDetectsTruncatedBlob(t *testing.T) {
+	content := []byte("only part of the photo")
+	backupDir, relPath := createPlainFixture(t, content, len(content)+100, 1700000000)
+	outputDir := t.TempDir()
+
+	extractor, err := CreateExtractor(ExtractConfig{
+		BackupPath: backupDir,
+		OutputPath: outputDir,
+		Logger:     logger.New(logger.Config{Level: logger.LevelInfo, Output: io.Discard}),
+	})
+	require.NoError(t, err)
+	defer extractor.Close()
+
+	summary, err := extractor.Extract()
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.FailedFiles)
+	require.Len(t, summary.CriticalErrors, 1)
+	assert.Contains(t, summary.CriticalErrors[0], "truncated")
+
+	_, err = os.Stat(filepath.Join(outputDir, relPath))
+	assert.True(t, os.IsNotExist(err), "truncated blobs should not be copied")
+}
+
+func TestExtractVerifiesRecordedDigest(t *testing.T) {
+	content := []byte("jpeg bytes")
+	backupDir, _ := createPlainFixture(t, content, len(content), 1700000000)
+
+	extract := func() ExtractSummary {
+		extractor, err := CreateExtractor(ExtractConfig{
+			BackupPath: backupDir,
+			OutputPath: t.TempDir(),
+			Verify:     true,
+			Logger:     logger.New(logger.Config{Level: logger.LevelInfo, Output: io.Discard}),
+		})
+		require.NoError(t, err)
+		defer extractor.Close()
+
+		summary, err := extractor.Extract()
+		require.NoError(t, err)
+		return *summary
+	}
+
+	summary := extract()
+	assert.Equal(t, 1, summary.ExtractedFiles)
+
+	// Same size, different contents than the device recorded
+	blobPath := filepath.Join(backupDir, "ab", "ab00000000000000000000000000000000000001")
+	require.NoError(t, os.WriteFile(blobPath, []byte("JPEG BYTES"), 0644))
+
+	summary = extract()
+	assert.Equal(t, 1, summary.FailedFiles)
+	require.Len(t, summary.CriticalErrors, 1)
+	assert.Contains(t, summary.CriticalErrors[0], "digest recorded by the device")
+}
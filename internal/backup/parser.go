@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -23,11 +24,19 @@ type BackupParser struct {
 	extractedAssets []*types.Asset
 	logger          *logger.Logger
 	paths           *utils.BackupPaths
+	encrypted       *EncryptedBackup // set when parsing an encrypted backup
+	media           *hashedMedia     // set when assets are read from a hashed backup in place
 }
 
 // CreateBackupParser creates a new backup parser for the given backup path.
 // It will automatically detect if the backup is extracted or not.
 func CreateBackupParser(backupPath string, logger *logger.Logger) (*BackupParser, error) {
+	return CreateBackupParserWithPassword(backupPath, "", logger)
+}
+
+// CreateBackupParserWithPassword creates a backup parser that can also open encrypted backups.
+// The password is only used when Manifest.plist marks the backup as encrypted.
+func CreateBackupParserWithPassword(backupPath, password string, logger *logger.Logger) (*BackupParser, error) {
 	// Resolve the backup path using smart directory walking
 	resolvedPath, err := resolveBackupPath(backupPath)
 	if err != nil {
@@ -48,6 +57,11 @@ func CreateBackupParser(backupPath string, logger *logger.Logger) (*BackupParser
 		return CreateExtractedBackupParser(backupPath, pathUtils.ExtractionMetadata(), logger)
 	}
 
+	// Encrypted backups need their databases decrypted before they can be read
+	if encrypted, err := isBackupEncrypted(backupPath); err == nil && encrypted {
+		return createEncryptedBackupParser(backupPath, password, pathUtils, logger)
+	}
+
 	// This is an original backup directory - use traditional parsing
 	// Find Photos.sqlite in the backup
 	photosDBPath, err := findPhotosDatabase(backupPath)
@@ -68,6 +82,16 @@ func CreateBackupParser(backupPath string, logger *logger.Logger) (*BackupParser
 		return nil, fmt.Errorf("failed to find DCIM directory: %w", err)
 	}
 
+	// Hashed backups are read in place: assets resolve to their backup files through Manifest.db
+	var media *hashedMedia
+	if dcimPath == backupPath {
+		if media, err = openHashedMedia(backupPath); err != nil {
+			photosDB.Close()
+			return nil, err
+		}
+		logger.Infof("Indexed %d media files from Manifest.db", len(media.records))
+	}
+
 	return &BackupParser{
 		backupPath:   backupPath,
 		photosDB:     photosDB,
@@ -76,6 +100,50 @@ func CreateBackupParser(backupPath string, logger *logger.Logger) (*BackupParser
 		isExtracted:  false,
 		logger:       logger,
 		paths:        pathUtils,
+		media:        media,
+	}, nil
+}
+
+// createEncryptedBackupParser unlocks an encrypted backup and parses its decrypted Photos database
+func createEncryptedBackupParser(backupPath, password string, pathUtils *utils.BackupPaths, logger *logger.Logger) (*BackupParser, error) {
+	encryptedBackup, err := OpenEncryptedBackup(backupPath, password)
+	if err != nil {
+		return nil, err
+	}
+
+	photosDBPath, err := encryptedBackup.DecryptPhotosDatabase()
+	if err != nil {
+		encryptedBackup.Close()
+		return nil, fmt.Errorf("failed to find Photos database: %w", err)
+	}
+
+	photosDB, err := photos.CreateDatabase(photosDBPath, logger)
+	if err != nil {
+		encryptedBackup.Close()
+		return nil, fmt.Errorf("failed to open Photos database: %w", err)
+	}
+
+	logger.Info("Unlocked encrypted backup")
+
+	// Like other hashed backups, media is resolved through Manifest.db from the backup root
+	media, err := loadHashedMedia(backupPath, encryptedBackup.Manifest())
+	if err != nil {
+		photosDB.Close()
+		encryptedBackup.Close()
+		return nil, err
+	}
+	media.encrypted = encryptedBackup
+
+	return &BackupParser{
+		backupPath:   backupPath,
+		photosDB:     photosDB,
+		dcimPath:     backupPath,
+		manifestPath: pathUtils.ManifestPlist(),
+		isExtracted:  false,
+		logger:       logger,
+		paths:        pathUtils,
+		encrypted:    encryptedBackup,
+		media:        media,
 	}, nil
 }
 
@@ -91,7 +159,8 @@ func CreateExtractedBackupParser(backupPath, metadataPath string, logger *logger
 	}
 
 	var extractionMetadata struct {
-		Assets []*types.Asset `json:"assets"`
+		Assets       []*types.Asset    `json:"assets"`
+		EscapedPaths map[string]string `json:"escaped_paths"`
 	}
 	if err := json.Unmarshal(data, &extractionMetadata); err != nil {
 		return nil, fmt.Errorf("failed to parse extraction metadata: %w", err)
@@ -109,6 +178,12 @@ func CreateExtractedBackupParser(backupPath, metadataPath string, logger *logger
 		pathUtils.MediaPhotoData(mediaDomain), // occasionally holds media
 	}
 
+	// Files whose names were escaped during extraction are indexed under their original names
+	originalPaths := make(map[string]string, len(extractionMetadata.EscapedPaths))
+	for original, escaped := range extractionMetadata.EscapedPaths {
+		originalPaths[escaped] = original
+	}
+
 	indexByFilename := make(map[string][]string)
 	indexByRelPath := make(map[string]string) // key: path after DCIM/ e.g. 100APPLE/IMG_0001.HEIC
 	indexedFiles := 0
@@ -116,7 +191,7 @@ func CreateExtractedBackupParser(backupPath, metadataPath string, logger *logger
 	for _, root := range dcimRoots {
 		if info, err := os.Stat(root); err == nil && info.IsDir() {
 			fileCount := 0
-			_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			_ = filepath.WalkDir(root, func(filePath string, d os.DirEntry, err error) error {
 				if err != nil {
 					return nil
 				}
@@ -124,12 +199,20 @@ func CreateExtractedBackupParser(backupPath, metadataPath string, logger *logger
 					return nil
 				}
 				filename := d.Name()
-				indexByFilename[filename] = append(indexByFilename[filename], path)
+				slashPath := filepath.ToSlash(filePath)
+				if len(originalPaths) > 0 {
+					if rel, err := filepath.Rel(backupPath, filePath); err == nil {
+						if original, ok := originalPaths[filepath.ToSlash(rel)]; ok {
+							filename = path.Base(original)
+							slashPath = "/" + original
+						}
+					}
+				}
+				indexByFilename[filename] = append(indexByFilename[filename], filePath)
 				// Build relative path after the first "DCIM" segment if present
-				slashPath := filepath.ToSlash(path)
 				if idx := strings.Index(slashPath, "/DCIM/"); idx != -1 {
 					rel := slashPath[idx+len("/DCIM/"):]
-					indexByRelPath[rel] = path
+					indexByRelPath[rel] = filePath
 				}
 				indexedFiles++
 				fileCount++
@@ -198,10 +281,17 @@ func CreateExtractedBackupParser(backupPath, metadataPath string, logger *logger
 
 // Close closes the backup parser and releases resources
 func (bp *BackupParser) Close() error {
+	var err error
 	if bp.photosDB != nil {
-		return bp.photosDB.Close()
+		err = bp.photosDB.Close()
 	}
-	return nil
+	// Closing the encrypted backup also removes its decrypted databases
+	if bp.encrypted != nil {
+		if closeErr := bp.encrypted.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
 }
 
 // ParseAssets extracts all assets from the backup
@@ -232,6 +322,7 @@ func (bp *BackupParser) ParseAssets() ([]*types.Asset, error) {
 				validAssets = append(validAssets, asset)
 			}
 		}
+		validAssets = dropLivePhotoCompanions(validAssets)
 		bp.logger.Infof("Asset processing completed. %d valid assets ready for upload.", len(validAssets))
 		return validAssets, nil
 	} // For original backup directories, use Photos database
@@ -268,6 +359,7 @@ func (bp *BackupParser) ParseAssets() ([]*types.Asset, error) {
 		}
 	}
 
+	validAssets = dropLivePhotoCompanions(validAssets)
 	bp.logger.Infof("Asset enrichment completed. %d valid assets ready for upload.", len(validAssets))
 	return validAssets, nil
 }
@@ -290,8 +382,36 @@ func (bp *BackupParser) ParseAssetsForExtraction() ([]*types.Asset, error) {
 	return assets, nil
 }
 
+// IsEncrypted reports whether the parser reads an encrypted backup
+func (bp *BackupParser) IsEncrypted() bool {
+	return bp.encrypted != nil
+}
+
+// PrepareFiles makes the source files at paths readable before they are read. Files of an
+// encrypted backup are decrypted into the parser's private temp directory; other backups are
+// read in place and need no preparation.
+func (bp *BackupParser) PrepareFiles(paths []string) error {
+	if bp.media == nil || bp.media.encrypted == nil {
+		return nil
+	}
+	return bp.media.prepare(paths)
+}
+
+// ReleaseFiles deletes the decrypted copies PrepareFiles made of the files at paths, so only
+// the files being read take up disk space. Close removes whatever is left.
+func (bp *BackupParser) ReleaseFiles(paths []string) error {
+	if bp.media == nil || bp.media.encrypted == nil {
+		return nil
+	}
+	return bp.media.release(paths)
+}
+
 // enrichAsset adds file system information to the asset
 func (bp *BackupParser) enrichAsset(asset *types.Asset) error {
+	if bp.media != nil {
+		return bp.enrichHashedAsset(asset)
+	}
+
 	// Check if the source file exists
 	info, err := os.Stat(asset.SourcePath)
 	if err != nil {
@@ -303,9 +423,112 @@ func (bp *BackupParser) enrichAsset(asset *types.Asset) error {
 	// Infer MIME type from extension
 	asset.MimeType = inferMimeType(asset.Filename)
 
+	// Resolve the paired video for Live Photos (stored next to the still with the same base name)
+	if asset.Type == types.AssetTypeLivePhoto {
+		bp.resolveLivePhotoVideo(asset)
+	}
+
+	// Resolve the rendered result of on-device edits
+	if asset.Flags.Edited {
+		bp.resolveEditedRender(asset)
+	}
+
 	return nil
 }
 
+// resolveEditedRender finds the FullSizeRender produced by on-device edits and records it on the asset
+// Renders live under PhotoData/Mutations mirroring the DCIM layout, e.g.
+// Media/DCIM/100APPLE/IMG_0001.HEIC -> Media/PhotoData/Mutations/DCIM/100APPLE/IMG_0001/Adjustments/FullSizeRender.jpg
+func (bp *BackupParser) resolveEditedRender(asset *types.Asset) {
+	adjustmentsDir := editedRenderDir(asset.SourcePath)
+	if adjustmentsDir == "" {
+		return
+	}
+
+	matches, err := filepath.Glob(filepath.Join(adjustmentsDir, "FullSizeRender.*"))
+	if err != nil || len(matches) == 0 {
+		bp.logger.Debugf("No edited render found for %s", asset.Filename)
+		return
+	}
+
+	// Prefer a render of the same kind as the original (Live Photos also get a FullSizeRender.mov)
+	wantVideo := asset.Type.IsVideo()
+	for _, match := range matches {
+		if (types.ClassifyByExtension(match) == types.AssetTypeVideo) != wantVideo {
+			continue
+		}
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		asset.EditedPath = match
+		asset.EditedSize = info.Size()
+		asset.EditedMimeType = inferMimeType(match)
+		return
+	}
+
+	bp.logger.Debugf("No edited render found for %s", asset.Filename)
+}
+
+// editedRenderDir returns the Adjustments directory holding the edited render for a DCIM source path
+// Returns an empty string if the path isn't inside a DCIM directory
+func editedRenderDir(sourcePath string) string {
+	slashPath := filepath.ToSlash(sourcePath)
+	idx := strings.LastIndex(slashPath, "/DCIM/")
+	if idx == -1 {
+		return ""
+	}
+
+	mediaRoot := slashPath[:idx]
+	rel := slashPath[idx+1:] // DCIM/100APPLE/IMG_0001.HEIC
+	rel = strings.TrimSuffix(rel, path.Ext(rel))
+	return filepath.FromSlash(path.Join(mediaRoot, "PhotoData", "Mutations", rel, "Adjustments"))
+}
+
+// resolveLivePhotoVideo finds the companion video of a Live Photo still and records it on the asset
+func (bp *BackupParser) resolveLivePhotoVideo(asset *types.Asset) {
+	dir := filepath.Dir(asset.SourcePath)
+	for _, candidate := range types.LivePhotoVideoCandidates(filepath.Base(asset.SourcePath)) {
+		videoPath := filepath.Join(dir, candidate)
+		info, err := os.Stat(videoPath)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		videoID := candidate
+		asset.LivePhotoVideoPath = videoPath
+		asset.LivePhotoVideoSize = info.Size()
+		asset.Flags.LivePhotoVideoID = &videoID
+		return
+	}
+
+	bp.logger.Debugf("No paired video found for Live Photo %s", asset.Filename)
+}
+
+// dropLivePhotoCompanions removes standalone video assets that are actually the paired video of a
+// Live Photo, so they are uploaded next to their still instead of as unrelated videos
+func dropLivePhotoCompanions(assets []*types.Asset) []*types.Asset {
+	companions := make(map[string]bool)
+	for _, asset := range assets {
+		if asset.HasLivePhotoVideo() {
+			companions[asset.LivePhotoVideoPath] = true
+		}
+	}
+	if len(companions) == 0 {
+		return assets
+	}
+
+	filtered := make([]*types.Asset, 0, len(assets))
+	for _, asset := range assets {
+		if asset.Type != types.AssetTypeLivePhoto && companions[asset.SourcePath] {
+			continue
+		}
+		filtered = append(filtered, asset)
+	}
+	return filtered
+}
+
 // resolveBackupPath automatically walks directory structure to find the actual backup directory
 // Supports common iPhone backup locations by looking for Manifest.db or Manifest.plist files
 func resolveBackupPath(inputPath string) (string, error) {
@@ -345,7 +568,7 @@ func resolveBackupPath(inputPath string) (string, error) {
 
 		// Safety check: if multiple backup directories exist, require explicit selection
 		if len(subDirs) > 1 {
-			return "", fmt.Errorf("multiple backup directories found in %s - please specify the exact backup directory path for safety", backupDir)
+			return "", fmt.Errorf("multiple backup directories found in %s - please specify the exact backup directory path or pick one with --device (see 'gh photos backups')", backupDir)
 		}
 
 		// If there's exactly one subdirectory, check if it's a backup directory
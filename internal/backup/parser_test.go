@@ -1,8 +1,12 @@
 package backup
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/grantbirki/gh-photos/internal/logger"
+	"github.com/grantbirki/gh-photos/internal/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -57,3 +61,51 @@ func TestInferMimeType(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveLivePhotoVideo(t *testing.T) {
+	dir := t.TempDir()
+	stillPath := filepath.Join(dir, "IMG_0001.HEIC")
+	videoPath := filepath.Join(dir, "IMG_0001.MOV")
+	assert.NoError(t, os.WriteFile(stillPath, []byte("still"), 0644))
+	assert.NoError(t, os.WriteFile(videoPath, []byte("motion"), 0644))
+
+	bp := &BackupParser{logger: logger.New(logger.Config{Level: logger.LevelError})}
+	live := &types.Asset{SourcePath: stillPath, Filename: "IMG_0001.HEIC", Type: types.AssetTypeLivePhoto}
+	bp.resolveLivePhotoVideo(live)
+
+	assert.Equal(t, videoPath, live.LivePhotoVideoPath)
+	assert.Equal(t, int64(len("motion")), live.LivePhotoVideoSize)
+	if assert.NotNil(t, live.Flags.LivePhotoVideoID) {
+		assert.Equal(t, "IMG_0001.MOV", *live.Flags.LivePhotoVideoID)
+	}
+
+	// The companion video parsed on its own must not be uploaded as a separate asset
+	companion := &types.Asset{SourcePath: videoPath, Filename: "IMG_0001.MOV", Type: types.AssetTypeVideo}
+	other := &types.Asset{SourcePath: filepath.Join(dir, "IMG_0002.MOV"), Filename: "IMG_0002.MOV", Type: types.AssetTypeVideo}
+	filtered := dropLivePhotoCompanions([]*types.Asset{live, companion, other})
+
+	assert.Equal(t, []*types.Asset{live, other}, filtered)
+}
+
+func TestResolveEditedRender(t *testing.T) {
+	dir := t.TempDir()
+	originalPath := filepath.Join(dir, "Media", "DCIM", "100APPLE", "IMG_0001.HEIC")
+	adjustmentsDir := filepath.Join(dir, "Media", "PhotoData", "Mutations", "DCIM", "100APPLE", "IMG_0001", "Adjustments")
+	assert.Equal(t, adjustmentsDir, editedRenderDir(originalPath))
+
+	assert.NoError(t, os.MkdirAll(adjustmentsDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(adjustmentsDir, "FullSizeRender.jpg"), []byte("render"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(adjustmentsDir, "FullSizeRender.mov"), []byte("motion render"), 0644))
+
+	bp := &BackupParser{logger: logger.New(logger.Config{Level: logger.LevelError})}
+	asset := &types.Asset{SourcePath: originalPath, Filename: "IMG_0001.HEIC", Type: types.AssetTypeLivePhoto, Flags: types.AssetFlags{Edited: true}}
+	bp.resolveEditedRender(asset)
+
+	assert.True(t, asset.HasEditedRender())
+	assert.Equal(t, filepath.Join(adjustmentsDir, "FullSizeRender.jpg"), asset.EditedPath)
+	assert.Equal(t, int64(len("render")), asset.EditedSize)
+	assert.Equal(t, "image/jpeg", asset.EditedMimeType)
+
+	// Paths outside DCIM have no Mutations counterpart
+	assert.Empty(t, editedRenderDir(filepath.Join(dir, "Media", "IMG_0002.HEIC")))
+}
@@ -0,0 +1,172 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/grantbirki/gh-photos/internal/types"
+)
+
+// ChangeType describes how an asset changed between two backups
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"   // only in the newer backup
+	ChangeDeleted ChangeType = "deleted" // only in the older backup
+	ChangeHidden  ChangeType = "hidden"  // hidden since the older backup
+	ChangeTrashed ChangeType = "trashed" // moved to Recently Deleted since the older backup
+	ChangeEdited  ChangeType = "edited"  // edited on the device since the older backup
+)
+
+// changeOrder is the order changes are reported in
+var changeOrder = []ChangeType{ChangeAdded, ChangeDeleted, ChangeHidden, ChangeTrashed, ChangeEdited}
+
+// Change is one asset that changed between two backups. An asset can appear once per change type.
+type Change struct {
+	Change       ChangeType      `json:"change"`
+	Identity     string          `json:"identity"` // UUID, or Photos database ID when the schema has none
+	ID           string          `json:"id"`
+	Filename     string          `json:"filename"`
+	Type         types.AssetType `json:"type"`
+	CreationDate time.Time       `json:"creation_date"`
+	FileSize     int64           `json:"file_size"`
+}
+
+// Result holds the changes from an older backup to a newer one
+type Result struct {
+	BackupA string             `json:"backup_a"`
+	BackupB string             `json:"backup_b"`
+	Summary map[ChangeType]int `json:"summary"`
+	Changes []Change           `json:"changes"`
+}
+
+// Compare matches the assets of backup A (older) and backup B (newer) by their stable identity
+// and returns what was added, deleted, hidden, trashed or edited in B
+func Compare(backupA, backupB string, before, after []*types.Asset) *Result {
+	result := &Result{
+		BackupA: backupA,
+		BackupB: backupB,
+		Summary: make(map[ChangeType]int),
+		Changes: []Change{},
+	}
+	for _, change := range changeOrder {
+		result.Summary[change] = 0
+	}
+
+	previous := make(map[string]*types.Asset, len(before))
+	for _, asset := range before {
+		previous[asset.StableID()] = asset
+	}
+	current := make(map[string]bool, len(after))
+
+	for _, asset := range after {
+		identity := asset.StableID()
+		current[identity] = true
+
+		old, ok := previous[identity]
+		if !ok {
+			result.add(ChangeAdded, asset)
+			continue
+		}
+		if asset.Flags.Hidden && !old.Flags.Hidden {
+			result.add(ChangeHidden, asset)
+		}
+		if asset.Flags.RecentlyDeleted && !old.Flags.RecentlyDeleted {
+			result.add(ChangeTrashed, asset)
+		}
+		if isNewlyEdited(old, asset) {
+			result.add(ChangeEdited, asset)
+		}
+	}
+
+	for _, asset := range before {
+		if !current[asset.StableID()] {
+			result.add(ChangeDeleted, asset)
+		}
+	}
+
+	rank := make(map[ChangeType]int, len(changeOrder))
+	for i, change := range changeOrder {
+		rank[change] = i
+	}
+	sort.SliceStable(result.Changes, func(i, j int) bool {
+		a, b := result.Changes[i], result.Changes[j]
+		if a.Change != b.Change {
+			return rank[a.Change] < rank[b.Change]
+		}
+		if !a.CreationDate.Equal(b.CreationDate) {
+			return a.CreationDate.Before(b.CreationDate)
+		}
+		return a.Identity < b.Identity
+	})
+
+	return result
+}
+
+// RequireUUIDs returns an error if an asset of backup has no UUID. Such assets fall back to
+// their Photos database ID, which isn't stable across backups, so a diff would match unrelated
+// assets and report changes that never happened.
+func RequireUUIDs(backup string, assets []*types.Asset) error {
+	missing := 0
+	for _, asset := range assets {
+		if asset.UUID == "" {
+			missing++
+		}
+	}
+	if missing > 0 {
+		return fmt.Errorf("%d of %d assets in %s have no UUID (Photos schema too old); backups can only be compared by UUID", missing, len(assets), backup)
+	}
+	return nil
+}
+
+// isNewlyEdited reports whether an asset was edited after the older backup: it gained edits,
+// or its edits were changed, which moves its modification date forward
+func isNewlyEdited(old, current *types.Asset) bool {
+	if !current.Flags.Edited {
+		return false
+	}
+	return !old.Flags.Edited || current.ModifiedDate.After(old.ModifiedDate)
+}
+
+// add records a change for asset
+func (r *Result) add(change ChangeType, asset *types.Asset) {
+	r.Changes = append(r.Changes, Change{
+		Change:       change,
+		Identity:     asset.StableID(),
+		ID:           asset.ID,
+		Filename:     asset.Filename,
+		Type:         asset.Type,
+		CreationDate: asset.CreationDate,
+		FileSize:     asset.FileSize,
+	})
+	r.Summary[change]++
+}
+
+// SyncSelection returns the identities of the assets a sync should upload to catch up with
+// backup B: the added and edited assets
+func (r *Result) SyncSelection() map[string]bool {
+	selection := make(map[string]bool)
+	for _, change := range r.Changes {
+		if change.Change == ChangeAdded || change.Change == ChangeEdited {
+			selection[change.Identity] = true
+		}
+	}
+	return selection
+}
+
+// LoadFromFile reads a result written by `gh photos diff --format json`
+func LoadFromFile(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diff file: %w", err)
+	}
+
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse diff file: %w", err)
+	}
+	return &result, nil
+}
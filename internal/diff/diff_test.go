@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grantbirki/gh-photos/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	day := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := []*types.Asset{
+		{ID: "1", UUID: "A", Filename: "IMG_0001.HEIC", CreationDate: day},
+		{ID: "2", UUID: "B", Filename: "IMG_0002.HEIC", CreationDate: day},
+		{ID: "3", UUID: "C", Filename: "IMG_0003.HEIC", CreationDate: day},
+		{ID: "4", UUID: "D", Filename: "IMG_0004.HEIC", CreationDate: day},
+		{ID: "5", UUID: "E", Filename: "IMG_0005.HEIC", CreationDate: day, ModifiedDate: day,
+			Flags: types.AssetFlags{Edited: true}},
+		{ID: "6", UUID: "F", Filename: "IMG_0006.HEIC", CreationDate: day, ModifiedDate: day,
+			Flags: types.AssetFlags{Edited: true}},
+	}
+	// Database rows are renumbered between backups; only the UUID identifies an asset
+	after := []*types.Asset{
+		{ID: "10", UUID: "B", Filename: "IMG_0002.HEIC", CreationDate: day, Flags: types.AssetFlags{Hidden: true}},
+		{ID: "11", UUID: "C", Filename: "IMG_0003.HEIC", CreationDate: day, Flags: types.AssetFlags{RecentlyDeleted: true}},
+		{ID: "12", UUID: "D", Filename: "IMG_0004.HEIC", CreationDate: day, ModifiedDate: day,
+			Flags: types.AssetFlags{Edited: true}},
+		{ID: "13", UUID: "E", Filename: "IMG_0005.HEIC", CreationDate: day, ModifiedDate: day.Add(time.Hour),
+			Flags: types.AssetFlags{Edited: true}},
+		{ID: "14", UUID: "F", Filename: "IMG_0006.HEIC", CreationDate: day, ModifiedDate: day,
+			Flags: types.AssetFlags{Edited: true}},
+		{ID: "15", UUID: "G", Filename: "IMG_0007.HEIC", CreationDate: day.Add(time.Hour)},
+	}
+
+	result := Compare("old", "new", before, after)
+
+	var got []string
+	for _, change := range result.Changes {
+		got = append(got, string(change.Change)+":"+change.Identity)
+	}
+	assert.Equal(t, []string{"added:G", "deleted:A", "hidden:B", "trashed:C", "edited:D", "edited:E"}, got)
+	assert.Equal(t, map[ChangeType]int{
+		ChangeAdded: 1, ChangeDeleted: 1, ChangeHidden: 1, ChangeTrashed: 1, ChangeEdited: 2,
+	}, result.Summary)
+	assert.Equal(t, "15", result.Changes[0].ID)
+
+	assert.Equal(t, map[string]bool{"G": true, "D": true, "E": true}, result.SyncSelection())
+}
+
+func TestCompareWithoutUUIDs(t *testing.T) {
+	// Older Photos schemas have no UUID column, so assets fall back to their database ID
+	before := []*types.Asset{{ID: "1"}, {ID: "2"}}
+	after := []*types.Asset{{ID: "2"}, {ID: "3"}}
+
+	result := Compare("old", "new", before, after)
+
+	assert.Equal(t, 1, result.Summary[ChangeAdded])
+	assert.Equal(t, 1, result.Summary[ChangeDeleted])
+	assert.Equal(t, map[string]bool{"3": true}, result.SyncSelection())
+
+	// The diff command refuses them, since database IDs aren't stable across backups
+	assert.ErrorContains(t, RequireUUIDs("old", before), "2 of 2 assets in old have no UUID")
+	assert.NoError(t, RequireUUIDs("new", []*types.Asset{{ID: "1", UUID: "A"}}))
+}
+
+func TestLoadFromFile(t *testing.T) {
+	result := Compare("old", "new", nil, []*types.Asset{{ID: "1", UUID: "A"}})
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "changes.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	loaded, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"A": true}, loaded.SyncSelection())
+
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+	_, err = LoadFromFile(path)
+	assert.ErrorContains(t, err, "failed to parse diff file")
+}
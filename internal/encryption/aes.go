@@ -0,0 +1,184 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// errIntegrityCheck is returned when an AES-wrapped key fails its integrity check
+var errIntegrityCheck = errors.New("key unwrap integrity check failed")
+
+// defaultIV is the RFC 3394 initial value
+var defaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// AESUnwrap unwraps a key with the RFC 3394 AES key wrap algorithm
+func AESUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, fmt.Errorf("wrapped key has invalid length %d", len(wrapped))
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key encryption key: %w", err)
+	}
+
+	n := len(wrapped)/8 - 1
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+	r := make([]byte, n*8)
+	copy(r, wrapped[8:])
+
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(a)^t)
+			copy(buf[8:], r[(i-1)*8:i*8])
+			block.Decrypt(buf, buf)
+			copy(a, buf[:8])
+			copy(r[(i-1)*8:i*8], buf[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(a, defaultIV) != 1 {
+		return nil, errIntegrityCheck
+	}
+	return r, nil
+}
+
+// AESWrap wraps a key with the RFC 3394 AES key wrap algorithm (the inverse of AESUnwrap)
+func AESWrap(kek, key []byte) ([]byte, error) {
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, fmt.Errorf("key has invalid length %d", len(key))
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key encryption key: %w", err)
+	}
+
+	n := len(key) / 8
+	a := make([]byte, 8)
+	copy(a, defaultIV)
+	r := make([]byte, n*8)
+	copy(r, key)
+
+	buf := make([]byte, 16)
+	for j := 0; j <= 5; j++ {
+		for i := 1; i <= n; i++ {
+			copy(buf[:8], a)
+			copy(buf[8:], r[(i-1)*8:i*8])
+			block.Encrypt(buf, buf)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(buf[:8])^t)
+			copy(r[(i-1)*8:i*8], buf[8:])
+		}
+	}
+
+	return append(a, r...), nil
+}
+
+// decryptChunkSize is how much ciphertext DecryptStream processes at a time (a multiple of the block size)
+const decryptChunkSize = 64 * 1024
+
+// DecryptStream decrypts AES-256-CBC data (zero IV, PKCS#7 padding) from src to dst, the scheme
+// backups use for Manifest.db and every file. When size is non-negative the output is truncated
+// to size bytes, the plaintext length recorded for the file. Returns the number of bytes written.
+func DecryptStream(dst io.Writer, src io.Reader, key []byte, size int64) (int64, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return 0, fmt.Errorf("invalid file key: %w", err)
+	}
+	mode := cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize))
+
+	var written int64
+	write := func(plain []byte) error {
+		if size >= 0 && written+int64(len(plain)) > size {
+			plain = plain[:max(size-written, 0)]
+		}
+		if len(plain) == 0 {
+			return nil
+		}
+		n, err := dst.Write(plain)
+		written += int64(n)
+		return err
+	}
+
+	buf := make([]byte, decryptChunkSize)
+	var pending []byte // last decrypted block, held back until we know whether it carries padding
+	for {
+		n, readErr := io.ReadFull(src, buf)
+		if n > 0 {
+			if n%aes.BlockSize != 0 {
+				return written, fmt.Errorf("ciphertext length is not a multiple of the block size")
+			}
+			chunk := buf[:n]
+			mode.CryptBlocks(chunk, chunk)
+
+			if err := write(pending); err != nil {
+				return written, err
+			}
+			pending = append(pending[:0], chunk[n-aes.BlockSize:]...)
+			if err := write(chunk[:n-aes.BlockSize]); err != nil {
+				return written, err
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return written, fmt.Errorf("failed to read ciphertext: %w", readErr)
+		}
+	}
+
+	// The recorded size already excludes padding; otherwise strip it from the final block
+	if size < 0 {
+		pending = unpad(pending)
+	}
+	if err := write(pending); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+// DecryptData decrypts an in-memory AES-256-CBC buffer, stripping PKCS#7 padding when present
+func DecryptData(key, ciphertext []byte) ([]byte, error) {
+	var out bytes.Buffer
+	if _, err := DecryptStream(&out, bytes.NewReader(ciphertext), key, -1); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// EncryptData encrypts data with AES-256-CBC (zero IV, PKCS#7 padding), the inverse of DecryptData
+func EncryptData(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file key: %w", err)
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(padded, padded)
+	return padded, nil
+}
+
+// unpad strips PKCS#7 padding from the final block, leaving it untouched if the padding is invalid
+func unpad(block []byte) []byte {
+	if len(block) == 0 {
+		return block
+	}
+	padding := int(block[len(block)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(block) {
+		return block
+	}
+	for _, b := range block[len(block)-padding:] {
+		if int(b) != padding {
+			return block
+		}
+	}
+	return block[:len(block)-padding]
+}
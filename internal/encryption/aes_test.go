@@ -0,0 +1,106 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestAESWrapRFC3394Vectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		kek     string
+		key     string
+		wrapped string
+	}{
+		{
+			name:    "128_bit_kek_128_bit_key",
+			kek:     "000102030405060708090A0B0C0D0E0F",
+			key:     "00112233445566778899AABBCCDDEEFF",
+			wrapped: "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+		},
+		{
+			name:    "256_bit_kek_256_bit_key",
+			kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:     "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+			wrapped: "28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kek := mustHex(t, tt.kek)
+			key := mustHex(t, tt.key)
+			wrapped := mustHex(t, tt.wrapped)
+
+			got, err := AESWrap(kek, key)
+			require.NoError(t, err)
+			assert.Equal(t, wrapped, got)
+
+			unwrapped, err := AESUnwrap(kek, wrapped)
+			require.NoError(t, err)
+			assert.Equal(t, key, unwrapped)
+		})
+	}
+}
+
+func TestAESUnwrapIntegrityFailure(t *testing.T) {
+	wrapped := mustHex(t, "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+	wrongKEK := mustHex(t, "0F0E0D0C0B0A09080706050403020100")
+
+	_, err := AESUnwrap(wrongKEK, wrapped)
+	assert.ErrorIs(t, err, errIntegrityCheck)
+
+	_, err = AESUnwrap(wrongKEK, wrapped[:16])
+	assert.Error(t, err)
+}
+
+func TestDecryptStream(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+
+	tests := []struct {
+		name      string
+		plaintext []byte
+	}{
+		{name: "empty", plaintext: []byte{}},
+		{name: "short", plaintext: []byte("hello")},
+		{name: "block_aligned", plaintext: bytes.Repeat([]byte("a"), 32)},
+		{name: "spans_chunks", plaintext: bytes.Repeat([]byte("0123456789"), decryptChunkSize/5+3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ciphertext, err := EncryptData(key, tt.plaintext)
+			require.NoError(t, err)
+
+			// Without a recorded size the padding is stripped
+			decrypted, err := DecryptData(key, ciphertext)
+			require.NoError(t, err)
+			assert.Equal(t, string(tt.plaintext), string(decrypted))
+
+			// With a recorded size the output is truncated to it
+			var out bytes.Buffer
+			written, err := DecryptStream(&out, bytes.NewReader(ciphertext), key, int64(len(tt.plaintext)))
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.plaintext)), written)
+			assert.Equal(t, string(tt.plaintext), out.String())
+		})
+	}
+}
+
+func TestDecryptStreamRejectsPartialBlock(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+
+	_, err := DecryptStream(&bytes.Buffer{}, bytes.NewReader(make([]byte, 20)), key, -1)
+	assert.Error(t, err)
+}
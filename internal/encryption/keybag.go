@@ -0,0 +1,214 @@
+package encryption
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrWrongPassword is returned when the backup password doesn't unlock the keybag
+var ErrWrongPassword = errors.New("incorrect backup password")
+
+// Wrap flags of a class key
+const (
+	WrapDevice   = 1 // key is wrapped with the device key (cannot be unwrapped off-device)
+	WrapPasscode = 2 // key is wrapped with the key derived from the backup password
+)
+
+// keyLength is the length of AES-256 keys derived from the password
+const keyLength = 32
+
+// wrappedKeyLength is the length of an AES-wrapped 256-bit key
+const wrappedKeyLength = 40
+
+// ClassKey is a protection class key from the keybag
+type ClassKey struct {
+	UUID       []byte
+	Class      uint32
+	Wrap       uint32
+	KeyType    uint32
+	WrappedKey []byte
+	Key        []byte // unwrapped key, set by Unlock
+}
+
+// Keybag is the BackupKeyBag stored in Manifest.plist of an encrypted backup.
+// It holds the protection class keys, wrapped with a key derived from the backup password.
+type Keybag struct {
+	UUID                 []byte
+	Type                 uint32
+	Wrap                 uint32
+	Salt                 []byte
+	Iterations           int
+	DoubleProtectionSalt []byte // DPSL, present on iOS 10.2+ backups
+	DoubleProtectionIter int    // DPIC
+	ClassKeys            map[uint32]*ClassKey
+	unlocked             bool
+}
+
+// ParseKeybag parses a keybag from its tag-length-value encoding:
+// a 4 byte tag, a 4 byte big-endian length and the value. Keybag attributes come first,
+// then one group of tags per class key, each group starting with a UUID.
+func ParseKeybag(data []byte) (*Keybag, error) {
+	kb := &Keybag{ClassKeys: make(map[uint32]*ClassKey)}
+	var current *ClassKey
+
+	storeClassKey := func() {
+		if current != nil {
+			kb.ClassKeys[current.Class] = current
+		}
+	}
+
+	for offset := 0; offset < len(data); {
+		if len(data)-offset < 8 {
+			return nil, fmt.Errorf("truncated keybag entry at offset %d", offset)
+		}
+		tag := string(data[offset : offset+4])
+		length := int(binary.BigEndian.Uint32(data[offset+4 : offset+8]))
+		offset += 8
+		if length < 0 || length > len(data)-offset {
+			return nil, fmt.Errorf("keybag entry %s length %d out of range", tag, length)
+		}
+		value := data[offset : offset+length]
+		offset += length
+
+		switch tag {
+		case "UUID":
+			if kb.UUID == nil {
+				kb.UUID = value
+				continue
+			}
+			storeClassKey()
+			current = &ClassKey{UUID: value}
+		case "CLAS", "WRAP", "KTYP", "WPKY":
+			if current == nil {
+				if tag == "WRAP" {
+					kb.Wrap = uint32Value(value)
+				}
+				continue
+			}
+			switch tag {
+			case "CLAS":
+				current.Class = uint32Value(value)
+			case "WRAP":
+				current.Wrap = uint32Value(value)
+			case "KTYP":
+				current.KeyType = uint32Value(value)
+			case "WPKY":
+				current.WrappedKey = value
+			}
+		case "TYPE":
+			kb.Type = uint32Value(value)
+		case "SALT":
+			kb.Salt = value
+		case "ITER":
+			kb.Iterations = int(uint32Value(value))
+		case "DPSL":
+			kb.DoubleProtectionSalt = value
+		case "DPIC":
+			kb.DoubleProtectionIter = int(uint32Value(value))
+		}
+	}
+	storeClassKey()
+
+	if kb.UUID == nil {
+		return nil, fmt.Errorf("keybag has no UUID")
+	}
+	if len(kb.Salt) == 0 || kb.Iterations <= 0 {
+		return nil, fmt.Errorf("keybag has no password salt or iteration count")
+	}
+	if len(kb.ClassKeys) == 0 {
+		return nil, fmt.Errorf("keybag has no class keys")
+	}
+
+	return kb, nil
+}
+
+// uint32Value decodes a big-endian integer value of up to 4 bytes
+func uint32Value(value []byte) uint32 {
+	var v uint32
+	for _, b := range value {
+		v = v<<8 | uint32(b)
+	}
+	return v
+}
+
+// DeriveKey derives the key that unwraps the class keys from the backup password.
+// iOS 10.2+ backups first run PBKDF2-SHA256 with the DPSL salt, then PBKDF2-SHA1 with SALT.
+func (kb *Keybag) DeriveKey(password string) ([]byte, error) {
+	secret := password
+	if len(kb.DoubleProtectionSalt) > 0 && kb.DoubleProtectionIter > 0 {
+		round1, err := pbkdf2.Key(sha256.New, password, kb.DoubleProtectionSalt, kb.DoubleProtectionIter, keyLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		secret = string(round1)
+	}
+
+	key, err := pbkdf2.Key(sha1.New, secret, kb.Salt, kb.Iterations, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
+
+// Unlock derives the password key and unwraps every class key that is protected by it.
+// Returns ErrWrongPassword when the password doesn't match.
+func (kb *Keybag) Unlock(password string) error {
+	passcodeKey, err := kb.DeriveKey(password)
+	if err != nil {
+		return err
+	}
+	return kb.UnlockWithKey(passcodeKey)
+}
+
+// UnlockWithKey unwraps the class keys with an already derived password key
+func (kb *Keybag) UnlockWithKey(passcodeKey []byte) error {
+	unwrapped := 0
+	for _, classKey := range kb.ClassKeys {
+		if classKey.Wrap&WrapPasscode == 0 || len(classKey.WrappedKey) == 0 {
+			continue
+		}
+		key, err := AESUnwrap(passcodeKey, classKey.WrappedKey)
+		if err != nil {
+			if errors.Is(err, errIntegrityCheck) {
+				return ErrWrongPassword
+			}
+			return fmt.Errorf("failed to unwrap class key %d: %w", classKey.Class, err)
+		}
+		classKey.Key = key
+		unwrapped++
+	}
+
+	if unwrapped == 0 {
+		return fmt.Errorf("keybag has no password-protected class keys")
+	}
+	kb.unlocked = true
+	return nil
+}
+
+// UnwrapKey unwraps a file or database key protected by the given class
+func (kb *Keybag) UnwrapKey(protectionClass uint32, wrappedKey []byte) ([]byte, error) {
+	if !kb.unlocked {
+		return nil, fmt.Errorf("keybag is locked")
+	}
+	classKey, ok := kb.ClassKeys[protectionClass]
+	if !ok || classKey.Key == nil {
+		return nil, fmt.Errorf("no key for protection class %d", protectionClass)
+	}
+	if len(wrappedKey) != wrappedKeyLength {
+		return nil, fmt.Errorf("wrapped key is %d bytes, expected %d", len(wrappedKey), wrappedKeyLength)
+	}
+	return AESUnwrap(classKey.Key, wrappedKey)
+}
+
+// UnwrapPrefixedKey unwraps a key stored with its protection class as a 4 byte little-endian prefix,
+// the layout of Manifest.plist's ManifestKey and of a file's EncryptionKey
+func (kb *Keybag) UnwrapPrefixedKey(data []byte) ([]byte, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("prefixed key is too short")
+	}
+	return kb.UnwrapKey(binary.LittleEndian.Uint32(data[:4]), data[4:])
+}
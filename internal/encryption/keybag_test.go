@@ -0,0 +1,130 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tlv encodes one keybag entry
+func tlv(tag string, value []byte) []byte {
+	out := make([]byte, 8, 8+len(value))
+	copy(out, tag)
+	binary.BigEndian.PutUint32(out[4:], uint32(len(value)))
+	return append(out, value...)
+}
+
+func tlvUint32(tag string, v uint32) []byte {
+	value := make([]byte, 4)
+	binary.BigEndian.PutUint32(value, v)
+	return tlv(tag, value)
+}
+
+// buildTestKeybag builds a keybag whose class keys 1 and 3 are wrapped with a key derived from password
+func buildTestKeybag(t *testing.T, password string, classKeys map[uint32][]byte) []byte {
+	t.Helper()
+
+	kb := &Keybag{
+		Salt:                 bytes.Repeat([]byte{0x01}, 20),
+		Iterations:           10,
+		DoubleProtectionSalt: bytes.Repeat([]byte{0x02}, 20),
+		DoubleProtectionIter: 10,
+	}
+	passcodeKey, err := kb.DeriveKey(password)
+	require.NoError(t, err)
+
+	var data []byte
+	data = append(data, tlvUint32("VERS", 3)...)
+	data = append(data, tlvUint32("TYPE", 1)...)
+	data = append(data, tlv("UUID", bytes.Repeat([]byte{0xAA}, 16))...)
+	data = append(data, tlvUint32("WRAP", 0)...)
+	data = append(data, tlv("SALT", kb.Salt)...)
+	data = append(data, tlvUint32("ITER", uint32(kb.Iterations))...)
+	data = append(data, tlv("DPSL", kb.DoubleProtectionSalt)...)
+	data = append(data, tlvUint32("DPIC", uint32(kb.DoubleProtectionIter))...)
+
+	for class := uint32(1); class <= 4; class++ {
+		key, ok := classKeys[class]
+		if !ok {
+			continue
+		}
+		wrapped, err := AESWrap(passcodeKey, key)
+		require.NoError(t, err)
+
+		data = append(data, tlv("UUID", bytes.Repeat([]byte{byte(class)}, 16))...)
+		data = append(data, tlvUint32("CLAS", class)...)
+		data = append(data, tlvUint32("WRAP", WrapPasscode)...)
+		data = append(data, tlvUint32("KTYP", 0)...)
+		data = append(data, tlv("WPKY", wrapped)...)
+	}
+	return data
+}
+
+func TestParseKeybag(t *testing.T) {
+	classKey := bytes.Repeat([]byte{0x11}, 32)
+	data := buildTestKeybag(t, "secret", map[uint32][]byte{1: classKey, 3: classKey})
+
+	kb, err := ParseKeybag(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(1), kb.Type)
+	assert.Equal(t, 10, kb.Iterations)
+	assert.Equal(t, 10, kb.DoubleProtectionIter)
+	assert.Len(t, kb.ClassKeys, 2)
+	assert.Equal(t, uint32(WrapPasscode), kb.ClassKeys[3].Wrap)
+	assert.Len(t, kb.ClassKeys[3].WrappedKey, wrappedKeyLength)
+}
+
+func TestParseKeybagErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated_header", data: []byte("UUID\x00\x00")},
+		{name: "length_out_of_range", data: tlv("UUID", []byte{1, 2})[:9]},
+		{name: "no_class_keys", data: append(tlv("UUID", []byte{1}), append(tlv("SALT", []byte{1}), tlvUint32("ITER", 1)...)...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKeybag(tt.data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeybagUnlock(t *testing.T) {
+	classKey := bytes.Repeat([]byte{0x33}, 32)
+	fileKey := bytes.Repeat([]byte{0x44}, 32)
+
+	t.Run("correct_password", func(t *testing.T) {
+		kb, err := ParseKeybag(buildTestKeybag(t, "secret", map[uint32][]byte{3: classKey}))
+		require.NoError(t, err)
+		require.NoError(t, kb.Unlock("secret"))
+		assert.Equal(t, classKey, kb.ClassKeys[3].Key)
+
+		// A file key wrapped with class 3, prefixed with its class as stored in Manifest.db
+		wrapped, err := AESWrap(classKey, fileKey)
+		require.NoError(t, err)
+		prefixed := append([]byte{3, 0, 0, 0}, wrapped...)
+
+		got, err := kb.UnwrapPrefixedKey(prefixed)
+		require.NoError(t, err)
+		assert.Equal(t, fileKey, got)
+
+		_, err = kb.UnwrapPrefixedKey(append([]byte{2, 0, 0, 0}, wrapped...))
+		assert.Error(t, err, "class without a key")
+	})
+
+	t.Run("wrong_password", func(t *testing.T) {
+		kb, err := ParseKeybag(buildTestKeybag(t, "secret", map[uint32][]byte{3: classKey}))
+		require.NoError(t, err)
+		assert.ErrorIs(t, kb.Unlock("not-the-password"), ErrWrongPassword)
+
+		_, err = kb.UnwrapKey(3, make([]byte, wrappedKeyLength))
+		assert.Error(t, err, "keybag stays locked")
+	})
+}
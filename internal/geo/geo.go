@@ -0,0 +1,285 @@
+package geo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// kmPerMile converts miles to kilometers
+const kmPerMile = 1.609344
+
+// Assets are placed in the nearest city within CityRadiusKm, and in the country of the nearest
+// city within CountryRadiusKm. Farther away, the city or country is unknown. The city radius
+// only covers a large city's own metro area, so a town missing from the gazetteer is left
+// unknown rather than attributed to a city it isn't part of.
+const (
+	CityRadiusKm    = 25.0
+	CountryRadiusKm = 500.0
+)
+
+// embeddedCities is generated from GeoNames cities15000 by script/update-gazetteer
+//
+//go:embed cities.tsv.gz
+var embeddedCities []byte
+
+//go:embed countries.tsv
+var embeddedCountries string
+
+// Place is a city in the gazetteer
+type Place struct {
+	City        string
+	CountryCode string // ISO 3166-1 alpha-2
+	Country     string
+	Latitude    float64
+	Longitude   float64
+}
+
+// cell is a 1x1 degree grid square used to index places
+type cell struct {
+	lat, lon int
+}
+
+// Gazetteer resolves coordinates to the nearest known city without any network service
+type Gazetteer struct {
+	places []Place
+	cells  map[cell][]int
+}
+
+var (
+	defaultOnce      sync.Once
+	defaultGazetteer *Gazetteer
+	countryNames     map[string]string
+)
+
+// Default returns the gazetteer embedded in the binary
+func Default() *Gazetteer {
+	defaultOnce.Do(func() {
+		countryNames = parseCountries(embeddedCountries)
+		cities, err := gzip.NewReader(bytes.NewReader(embeddedCities))
+		if err != nil {
+			panic(fmt.Sprintf("embedded gazetteer is invalid: %v", err))
+		}
+		g, err := parseCities(cities)
+		if err != nil {
+			panic(fmt.Sprintf("embedded gazetteer is invalid: %v", err))
+		}
+		defaultGazetteer = g
+	})
+	return defaultGazetteer
+}
+
+// LoadGeoNames reads a GeoNames cities dump (such as cities15000.txt from
+// https://download.geonames.org/export/dump/) into a gazetteer
+func LoadGeoNames(path string) (*Gazetteer, error) {
+	Default() // country names
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gazetteer: %w", err)
+	}
+	defer file.Close()
+
+	g := &Gazetteer{cells: make(map[cell][]int)}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // alternate names can make lines long
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 9 {
+			continue
+		}
+		lat, latErr := strconv.ParseFloat(fields[4], 64)
+		lon, lonErr := strconv.ParseFloat(fields[5], 64)
+		if latErr != nil || lonErr != nil {
+			return nil, fmt.Errorf("invalid coordinates on line %d of %s", line, path)
+		}
+		g.add(fields[1], fields[8], lat, lon)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read gazetteer: %w", err)
+	}
+	if len(g.places) == 0 {
+		return nil, fmt.Errorf("no cities found in %s (expected the GeoNames tab-separated format)", path)
+	}
+	return g, nil
+}
+
+// parseCities reads the embedded name, country code, latitude, longitude format
+func parseCities(r io.Reader) (*Gazetteer, error) {
+	g := &Gazetteer{cells: make(map[cell][]int)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid line %q", text)
+		}
+		lat, latErr := strconv.ParseFloat(fields[2], 64)
+		lon, lonErr := strconv.ParseFloat(fields[3], 64)
+		if latErr != nil || lonErr != nil {
+			return nil, fmt.Errorf("invalid coordinates in line %q", text)
+		}
+		g.add(fields[0], fields[1], lat, lon)
+	}
+	return g, scanner.Err()
+}
+
+// parseCountries reads the code, name country list
+func parseCountries(data string) map[string]string {
+	names := make(map[string]string)
+	for _, text := range strings.Split(data, "\n") {
+		if code, name, ok := strings.Cut(text, "\t"); ok && !strings.HasPrefix(text, "#") {
+			names[code] = name
+		}
+	}
+	return names
+}
+
+// add indexes a city
+func (g *Gazetteer) add(city, countryCode string, lat, lon float64) {
+	country := countryNames[countryCode]
+	if country == "" {
+		country = countryCode
+	}
+	g.places = append(g.places, Place{
+		City:        city,
+		CountryCode: countryCode,
+		Country:     country,
+		Latitude:    lat,
+		Longitude:   lon,
+	})
+	key := cellOf(lat, lon)
+	g.cells[key] = append(g.cells[key], len(g.places)-1)
+}
+
+// Len returns the number of cities in the gazetteer
+func (g *Gazetteer) Len() int {
+	return len(g.places)
+}
+
+// Nearest returns the city closest to the coordinates within maxKm, and its distance in kilometers
+func (g *Gazetteer) Nearest(lat, lon, maxKm float64) (Place, float64, bool) {
+	// Only the grid cells that can hold a city within maxKm are searched
+	latSpan := maxKm / (math.Pi * earthRadiusKm / 180)
+	lonSpan := 360.0
+	if cos := math.Cos(toRadians(math.Min(math.Abs(lat)+latSpan, 90))); cos > 0 {
+		lonSpan = math.Min(latSpan/cos, 360)
+	}
+
+	minLat := int(math.Floor(math.Max(lat-latSpan, -90)))
+	maxLat := int(math.Floor(math.Min(lat+latSpan, 90)))
+	minLon, maxLon := int(math.Floor(lon-lonSpan)), int(math.Floor(lon+lonSpan))
+	if lonSpan >= 180 {
+		minLon, maxLon = -180, 179
+	}
+
+	best, bestDistance, found := Place{}, maxKm, false
+	for cellLat := minLat; cellLat <= maxLat; cellLat++ {
+		for cellLon := minLon; cellLon <= maxLon; cellLon++ {
+			for _, i := range g.cells[cell{cellLat, wrapLongitude(cellLon)}] {
+				place := g.places[i]
+				if distance := Distance(lat, lon, place.Latitude, place.Longitude); distance <= bestDistance {
+					best, bestDistance, found = place, distance, true
+				}
+			}
+		}
+	}
+	return best, bestDistance, found
+}
+
+// Resolve returns the city and country of the coordinates. Either is empty when no city is
+// close enough (see CityRadiusKm and CountryRadiusKm).
+func (g *Gazetteer) Resolve(lat, lon float64) (city, country string) {
+	place, distance, ok := g.Nearest(lat, lon, CountryRadiusKm)
+	if !ok {
+		return "", ""
+	}
+	if distance <= CityRadiusKm {
+		city = place.City
+	}
+	return city, place.Country
+}
+
+// Area is a circle on the Earth's surface
+type Area struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// ParseArea parses "lat,lon,radius", where the radius is in kilometers unless it ends in "mi"
+// (an optional "km" suffix is accepted), e.g. "39.7392,-104.9903,25km"
+func ParseArea(value string) (Area, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		return Area{}, fmt.Errorf("expected lat,lon,radius but got %q", value)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return Area{}, fmt.Errorf("invalid latitude %q (must be between -90 and 90)", parts[0])
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return Area{}, fmt.Errorf("invalid longitude %q (must be between -180 and 180)", parts[1])
+	}
+
+	radius := strings.ToLower(strings.TrimSpace(parts[2]))
+	unit := 1.0
+	switch {
+	case strings.HasSuffix(radius, "mi"):
+		radius, unit = strings.TrimSuffix(radius, "mi"), kmPerMile
+	case strings.HasSuffix(radius, "km"):
+		radius = strings.TrimSuffix(radius, "km")
+	}
+	distance, err := strconv.ParseFloat(strings.TrimSpace(radius), 64)
+	if err != nil || distance <= 0 {
+		return Area{}, fmt.Errorf("invalid radius %q (must be a positive distance, e.g. 25 or 10mi)", parts[2])
+	}
+
+	return Area{Latitude: lat, Longitude: lon, RadiusKm: distance * unit}, nil
+}
+
+// Contains reports whether the coordinates lie within the area
+func (a Area) Contains(lat, lon float64) bool {
+	return Distance(a.Latitude, a.Longitude, lat, lon) <= a.RadiusKm
+}
+
+// Distance returns the great-circle distance between two coordinates in kilometers
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// cellOf returns the grid cell holding the coordinates
+func cellOf(lat, lon float64) cell {
+	return cell{int(math.Floor(lat)), wrapLongitude(int(math.Floor(lon)))}
+}
+
+// wrapLongitude maps a whole degree of longitude into [-180, 180)
+func wrapLongitude(lon int) int {
+	return ((lon+180)%360+360)%360 - 180
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,131 @@
+package geo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultGazetteer(t *testing.T) {
+	g := Default()
+	assert.Greater(t, g.Len(), 500)
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		city     string
+		country  string
+	}{
+		{"city center", 39.7392, -104.9903, "Denver", "United States"},
+		{"another country", 48.8534, 2.3488, "Paris", "France"},
+		{"island", -18.1416, 178.4415, "Suva", "Fiji"},
+		{"countryside", 44.4280, -110.5885, "", "United States"},
+		{"open ocean", 0, -140, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			city, country := g.Resolve(tt.lat, tt.lon)
+			assert.Equal(t, tt.city, city)
+			assert.Equal(t, tt.country, country)
+		})
+	}
+}
+
+func TestResolveCityRadius(t *testing.T) {
+	Default() // country names
+	g, err := parseCities(strings.NewReader("Denver\tUS\t39.7392\t-104.9842\n"))
+	require.NoError(t, err)
+
+	city, country := g.Resolve(39.6133, -105.0166) // Littleton, 14 km away
+	assert.Equal(t, "Denver", city)
+	assert.Equal(t, "United States", country)
+
+	// A town missing from the gazetteer isn't attributed to a city 100 km away
+	city, country = g.Resolve(40.5853, -105.0844) // Fort Collins
+	assert.Empty(t, city)
+	assert.Equal(t, "United States", country)
+}
+
+func TestNearestWrapsLongitude(t *testing.T) {
+	g, err := parseCities(strings.NewReader("West\tUS\t51.0\t179.9\nEast\tRU\t51.0\t-179.9\n"))
+	require.NoError(t, err)
+
+	place, distance, ok := g.Nearest(51.0, -179.95, 50)
+	require.True(t, ok)
+	assert.Equal(t, "East", place.City)
+	assert.Less(t, distance, 5.0)
+
+	place, _, ok = g.Nearest(51.0, 179.95, 50)
+	require.True(t, ok)
+	assert.Equal(t, "West", place.City)
+
+	_, _, ok = g.Nearest(40.0, 0, 50)
+	assert.False(t, ok)
+}
+
+func TestLoadGeoNames(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cities15000.txt")
+	data := "5419384\tDenver\tDenver\tDenver,Denvur\t39.73915\t-104.9847\tP\tPPLA\tUS\t\tCO\t031\t\t\t715522\t1636\t1609\tAmerica/Denver\t2022-07-05\n" +
+		"5417598\tColorado Springs\tColorado Springs\t\t38.83388\t-104.82136\tP\tPPLA2\tUS\t\tCO\t041\t\t\t478961\t1832\t1829\tAmerica/Denver\t2019-09-05\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	g, err := LoadGeoNames(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, g.Len())
+
+	city, country := g.Resolve(38.9, -104.8)
+	assert.Equal(t, "Colorado Springs", city)
+	assert.Equal(t, "United States", country)
+
+	require.NoError(t, os.WriteFile(path, []byte("not a gazetteer\n"), 0644))
+	_, err = LoadGeoNames(path)
+	assert.Error(t, err)
+
+	_, err = LoadGeoNames(filepath.Join(dir, "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestParseArea(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Area
+		wantErr  bool
+	}{
+		{value: "39.7392,-104.9903,25", expected: Area{39.7392, -104.9903, 25}},
+		{value: "39.7392, -104.9903, 25km", expected: Area{39.7392, -104.9903, 25}},
+		{value: "51.5,-0.12,10mi", expected: Area{51.5, -0.12, 10 * kmPerMile}},
+		{value: "39.7392,-104.9903", wantErr: true},
+		{value: "91,0,5", wantErr: true},
+		{value: "0,181,5", wantErr: true},
+		{value: "0,0,0", wantErr: true},
+		{value: "0,0,far", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			area, err := ParseArea(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected.Latitude, area.Latitude, 1e-9)
+			assert.InDelta(t, tt.expected.Longitude, area.Longitude, 1e-9)
+			assert.InDelta(t, tt.expected.RadiusKm, area.RadiusKm, 1e-9)
+		})
+	}
+}
+
+func TestAreaContains(t *testing.T) {
+	area := Area{Latitude: 39.7392, Longitude: -104.9903, RadiusKm: 25}
+	assert.True(t, area.Contains(39.7392, -104.9903))
+	assert.True(t, area.Contains(39.6133, -105.0166))  // Littleton, ~14 km
+	assert.False(t, area.Contains(40.0150, -105.2705)) // Boulder, ~39 km
+
+	// London to Paris is about 344 km
+	assert.InDelta(t, 344, Distance(51.5074, -0.1278, 48.8566, 2.3522), 2)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
 	"time"
 
 	"github.com/grantbirki/gh-photos/internal/types"
@@ -11,17 +13,31 @@ import (
 
 // Entry represents a single entry in the manifest
 type Entry struct {
-	SourcePath   string           `json:"source_path"`
-	TargetPath   string           `json:"target_path"`
-	Filename     string           `json:"filename"`
-	AssetType    types.AssetType  `json:"asset_type"`
-	CreationDate time.Time        `json:"creation_date"`
-	FileSize     int64            `json:"file_size"`
-	Checksum     string           `json:"checksum,omitempty"`
-	MimeType     string           `json:"mime_type"`
-	Status       OperationStatus  `json:"status"`
-	Flags        types.AssetFlags `json:"flags"`
-	Error        string           `json:"error,omitempty"`
+	UUID          string           `json:"uuid"`                       // Stable asset identity (Photos UUID, or Z_PK when the schema has none)
+	CloudGUID     string           `json:"cloud_asset_guid,omitempty"` // iCloud Photos asset GUID
+	PhotosID      string           `json:"photos_id,omitempty"`        // Photos database primary key (Z_PK), for debugging
+	SourcePath    string           `json:"source_path"`
+	TargetPath    string           `json:"target_path"`
+	Filename      string           `json:"filename"`
+	AssetType     types.AssetType  `json:"asset_type"`
+	CreationDate  time.Time        `json:"creation_date"`
+	FileSize      int64            `json:"file_size"`
+	Checksum      string           `json:"checksum,omitempty"`
+	MimeType      string           `json:"mime_type"`
+	Status        OperationStatus  `json:"status"`
+	Flags         types.AssetFlags `json:"flags"`
+	Albums        []string         `json:"albums,omitempty"`
+	People        []string         `json:"people,omitempty"`
+	Location      *types.Location  `json:"location,omitempty"`
+	Country       string           `json:"country,omitempty"`
+	City          string           `json:"city,omitempty"`
+	LivePhotoRole string           `json:"live_photo_role,omitempty"` // "still" or "video" for Live Photo components
+	PairedPath    string           `json:"paired_path,omitempty"`     // Target path of the other Live Photo component
+	Edited        bool             `json:"edited,omitempty"`          // Entry is the edited render of the asset
+	Error         string           `json:"error,omitempty"`
+
+	// Status of the copy on the favorites remote (only set for favorites when --favorites-remote is used)
+	FavoritesStatus OperationStatus `json:"favorites_status,omitempty"`
 }
 
 // OperationStatus represents the status of an operation on an asset
@@ -58,6 +74,21 @@ type Config struct {
 	EndDate                *time.Time `json:"end_date,omitempty"`
 	AssetTypes             []string   `json:"asset_types,omitempty"`
 	PathGranularity        string     `json:"path_granularity,omitempty"`
+	Albums                 []string   `json:"albums,omitempty"`
+	People                 []string   `json:"people,omitempty"`
+	OrganizeByAlbum        bool       `json:"organize_by_album,omitempty"`
+	LivePhotoMode          string     `json:"live_photo_mode,omitempty"`
+	BurstMode              string     `json:"burst_mode,omitempty"`
+	EditsMode              string     `json:"edits_mode,omitempty"`
+	Timezone               string     `json:"timezone,omitempty"`
+	FilenameTemplate       string     `json:"filename_template,omitempty"`
+	PathTemplate           string     `json:"path_template,omitempty"`
+	RemotePreScan          bool       `json:"remote_pre_scan,omitempty"`
+	AssetList              string     `json:"asset_list,omitempty"`
+	Near                   string     `json:"near,omitempty"`
+	Gazetteer              string     `json:"gazetteer,omitempty"`
+	FavoritesOnly          bool       `json:"favorites_only,omitempty"`
+	FavoritesRemote        string     `json:"favorites_remote,omitempty"`
 }
 
 // Summary provides aggregate statistics about the operation
@@ -108,10 +139,17 @@ func (g *Generator) CreateManifest(assets []*types.Asset) *Manifest {
 	}
 
 	for _, asset := range assets {
-		// Generate target path (root prefix removed)
-		targetPath := asset.GenerateTargetPath(granularity)
+		// Prefer the target path already computed during filtering (it reflects layout options),
+		// otherwise generate one from the configured granularity (root prefix removed)
+		targetPath := asset.TargetPath
+		if targetPath == "" {
+			targetPath = asset.GenerateTargetPath(granularity, g.config.FilenameTemplate)
+		}
 
 		entry := Entry{
+			UUID:         asset.StableID(),
+			CloudGUID:    asset.CloudAssetGUID,
+			PhotosID:     asset.ID,
 			SourcePath:   asset.SourcePath,
 			TargetPath:   targetPath,
 			Filename:     asset.Filename,
@@ -122,31 +160,126 @@ func (g *Generator) CreateManifest(assets []*types.Asset) *Manifest {
 			MimeType:     asset.MimeType,
 			Status:       StatusPending,
 			Flags:        asset.Flags,
+			Albums:       asset.Albums,
+			People:       asset.People,
+			Location:     asset.Location,
+			Country:      asset.Country,
+			City:         asset.City,
+		}
+
+		entries := []Entry{entry}
+		if asset.HasLivePhotoVideo() {
+			entries = g.livePhotoEntries(asset, entry)
+		}
+		if asset.HasEditedRender() {
+			entries = g.editedEntries(asset, entries)
 		}
 
-		manifest.Entries = append(manifest.Entries, entry)
-		manifest.Summary.TotalSize += asset.FileSize
+		for _, e := range entries {
+			manifest.Entries = append(manifest.Entries, e)
+			manifest.Summary.TotalSize += e.FileSize
+		}
 	}
 
+	manifest.Summary.TotalAssets = len(manifest.Entries)
 	manifest.Summary.ProcessedAssets = len(manifest.Entries)
 	return manifest
 }
 
+// editedEntries applies the configured edits mode to the entries of an edited asset.
+// The edited render is placed next to the original with types.EditedSuffix added to its name.
+func (g *Generator) editedEntries(asset *types.Asset, entries []Entry) []Entry {
+	originalIndex := -1
+	for i, e := range entries {
+		if e.SourcePath == asset.SourcePath {
+			originalIndex = i
+			break
+		}
+	}
+	if originalIndex == -1 {
+		return entries // original not uploaded (e.g. Live Photo video only)
+	}
+
+	edited := entries[originalIndex]
+	edited.SourcePath = asset.EditedPath
+	edited.TargetPath = types.EditedTargetPath(edited.TargetPath, asset.EditedRenderFilename())
+	edited.Filename = path.Base(edited.TargetPath)
+	edited.FileSize = asset.EditedSize
+	edited.Checksum = ""
+	edited.MimeType = asset.EditedMimeType
+	edited.LivePhotoRole = ""
+	edited.PairedPath = ""
+	edited.Edited = true
+
+	switch types.EditsMode(g.config.EditsMode) {
+	case types.EditsModeEdited:
+		result := append([]Entry{}, entries...)
+		result[originalIndex] = edited
+		return result
+	case types.EditsModeBoth:
+		return append(entries, edited)
+	default: // original
+		return entries
+	}
+}
+
+// livePhotoEntries returns the entries to upload for a Live Photo according to the configured mode.
+// The video is placed in the same folder as the still and shares its base name.
+func (g *Generator) livePhotoEntries(asset *types.Asset, still Entry) []Entry {
+	video := still
+	video.SourcePath = asset.LivePhotoVideoPath
+	video.TargetPath = types.LivePhotoVideoTargetPath(still.TargetPath, asset.LivePhotoVideoFilename())
+	video.Filename = path.Base(video.TargetPath)
+	video.FileSize = asset.LivePhotoVideoSize
+	video.Checksum = ""
+	video.MimeType = "video/quicktime"
+	video.LivePhotoRole = types.LivePhotoRoleVideo
+
+	switch types.LivePhotoMode(g.config.LivePhotoMode) {
+	case types.LivePhotoModeStill:
+		return []Entry{still}
+	case types.LivePhotoModeVideo:
+		return []Entry{video}
+	default: // both
+		still.LivePhotoRole = types.LivePhotoRoleStill
+		still.PairedPath = video.TargetPath
+		video.PairedPath = still.TargetPath
+		return []Entry{still, video}
+	}
+}
+
 // SaveToFile writes the manifest to a JSON file
+// The manifest is written to a temporary file and renamed into place, so a crash
+// mid-write never leaves a truncated manifest behind.
 func (m *Manifest) SaveToFile(filePath string) error {
-	file, err := os.Create(filePath)
+	file, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp-*")
 	if err != nil {
 		return fmt.Errorf("failed to create manifest file %s: %w", filePath, err)
 	}
-	defer file.Close()
+	tempPath := file.Name()
+	defer os.Remove(tempPath)
 
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 
 	if err := encoder.Encode(m); err != nil {
+		file.Close()
 		return fmt.Errorf("failed to encode manifest: %w", err)
 	}
 
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to set manifest file permissions: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write manifest file %s: %w", filePath, err)
+	}
+
+	if err := os.Rename(tempPath, filePath); err != nil {
+		return fmt.Errorf("failed to replace manifest file %s: %w", filePath, err)
+	}
+
 	return nil
 }
 
@@ -179,6 +312,27 @@ func (m *Manifest) UpdateEntry(index int, status OperationStatus, errorMsg strin
 	}
 }
 
+// IsComplete reports whether the status means the entry is already on the remote
+func (s OperationStatus) IsComplete() bool {
+	return s == StatusUploaded || s == StatusVerified
+}
+
+// ResetIncomplete marks every entry that is not uploaded or verified as pending again
+// and clears its previous error, so a resumed sync retries it. Returns the number of reset entries.
+func (m *Manifest) ResetIncomplete() int {
+	reset := 0
+	for i := range m.Entries {
+		if m.Entries[i].Status.IsComplete() {
+			continue
+		}
+		m.Entries[i].Status = StatusPending
+		m.Entries[i].Error = ""
+		reset++
+	}
+	m.updateSummary()
+	return reset
+}
+
 // updateSummary recalculates the summary statistics
 func (m *Manifest) updateSummary() {
 	summary := Summary{
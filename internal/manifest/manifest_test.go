@@ -1,6 +1,8 @@
 package manifest
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -23,6 +25,7 @@ func TestGenerator_CreateManifest(t *testing.T) {
 	assets := []*types.Asset{
 		{
 			ID:           "1",
+			UUID:         "5A1F2C3D-0000-4000-8000-000000000001",
 			SourcePath:   "/test/backup/IMG_001.HEIC",
 			Filename:     "IMG_001.HEIC",
 			Type:         types.AssetTypePhoto,
@@ -52,6 +55,11 @@ func TestGenerator_CreateManifest(t *testing.T) {
 	assert.Equal(t, int64(1024+2048), manifest.Summary.TotalSize)
 	assert.Len(t, manifest.Entries, 2)
 
+	// Entries are keyed by the Photos UUID, falling back to Z_PK, which is kept for debugging
+	assert.Equal(t, "5A1F2C3D-0000-4000-8000-000000000001", manifest.Entries[0].UUID)
+	assert.Equal(t, "1", manifest.Entries[0].PhotosID)
+	assert.Equal(t, "2", manifest.Entries[1].UUID)
+
 	// Check that target paths are generated correctly
 	for _, entry := range manifest.Entries {
 		assert.Contains(t, entry.TargetPath, now.Format("2006"))
@@ -106,6 +114,58 @@ func TestManifest_GetFilteredEntries(t *testing.T) {
 	assert.Len(t, skipped, 1)
 }
 
+func TestManifest_ResetIncomplete(t *testing.T) {
+	manifest := &Manifest{
+		Entries: []Entry{
+			{SourcePath: "/test/file1.jpg", Status: StatusUploaded, FileSize: 1000},
+			{SourcePath: "/test/file2.jpg", Status: StatusFailed, Error: "network error", FileSize: 2000},
+			{SourcePath: "/test/file3.jpg", Status: StatusVerified, FileSize: 3000},
+			{SourcePath: "/test/file4.jpg", Status: StatusSkipped, FileSize: 4000},
+			{SourcePath: "/test/file5.jpg", Status: StatusPending, FileSize: 5000},
+		},
+	}
+
+	reset := manifest.ResetIncomplete()
+
+	assert.Equal(t, 3, reset)
+	assert.Equal(t, StatusUploaded, manifest.Entries[0].Status)
+	assert.Equal(t, StatusPending, manifest.Entries[1].Status)
+	assert.Equal(t, "", manifest.Entries[1].Error)
+	assert.Equal(t, StatusVerified, manifest.Entries[2].Status)
+	assert.Equal(t, StatusPending, manifest.Entries[3].Status)
+	assert.Equal(t, 2, manifest.Summary.ProcessedAssets)
+	assert.Equal(t, int64(1000), manifest.Summary.UploadedSize)
+}
+
+func TestManifest_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	manifest := &Manifest{
+		BackupPath:   "/test/backup",
+		RemoteTarget: "gdrive:Photos",
+		Entries: []Entry{
+			{SourcePath: "/test/file1.jpg", Status: StatusUploaded},
+			{SourcePath: "/test/file2.jpg", Status: StatusPending},
+		},
+	}
+
+	assert.NoError(t, manifest.SaveToFile(path))
+
+	// Overwriting an existing manifest replaces it in place
+	manifest.UpdateEntry(1, StatusFailed, "network error")
+	assert.NoError(t, manifest.SaveToFile(path))
+
+	loaded, err := LoadFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "gdrive:Photos", loaded.RemoteTarget)
+	assert.Equal(t, StatusFailed, loaded.Entries[1].Status)
+	assert.Equal(t, "network error", loaded.Entries[1].Error)
+
+	// No temporary files are left next to the manifest
+	files, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
 func TestHumanizeBytes(t *testing.T) {
 	tests := []struct {
 		bytes    int64
@@ -125,3 +185,102 @@ func TestHumanizeBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerator_CreateManifest_LivePhotoModes(t *testing.T) {
+	creation := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	newAsset := func() *types.Asset {
+		return &types.Asset{
+			ID:                 "1",
+			SourcePath:         "/test/backup/IMG_0001.HEIC",
+			Filename:           "IMG_0001.HEIC",
+			Type:               types.AssetTypeLivePhoto,
+			CreationDate:       creation,
+			FileSize:           1000,
+			MimeType:           "image/heif",
+			LivePhotoVideoPath: "/test/backup/IMG_0001.MOV",
+			LivePhotoVideoSize: 3000,
+		}
+	}
+
+	t.Run("both", func(t *testing.T) {
+		generator := CreateGenerator("/test/backup", "gdrive:Photos", Config{LivePhotoMode: "both"})
+		m := generator.CreateManifest([]*types.Asset{newAsset()})
+
+		assert.Len(t, m.Entries, 2)
+		still, video := m.Entries[0], m.Entries[1]
+		assert.Equal(t, "2024/03/18/live_photos/IMG_0001.HEIC", still.TargetPath)
+		assert.Equal(t, "2024/03/18/live_photos/IMG_0001.MOV", video.TargetPath)
+		assert.Equal(t, types.LivePhotoRoleStill, still.LivePhotoRole)
+		assert.Equal(t, types.LivePhotoRoleVideo, video.LivePhotoRole)
+		assert.Equal(t, video.TargetPath, still.PairedPath)
+		assert.Equal(t, still.TargetPath, video.PairedPath)
+		assert.Equal(t, "/test/backup/IMG_0001.MOV", video.SourcePath)
+		assert.Equal(t, "video/quicktime", video.MimeType)
+		assert.Equal(t, int64(4000), m.Summary.TotalSize)
+		assert.Equal(t, 2, m.Summary.TotalAssets)
+	})
+
+	t.Run("still", func(t *testing.T) {
+		generator := CreateGenerator("/test/backup", "gdrive:Photos", Config{LivePhotoMode: "still"})
+		m := generator.CreateManifest([]*types.Asset{newAsset()})
+
+		assert.Len(t, m.Entries, 1)
+		assert.Equal(t, "/test/backup/IMG_0001.HEIC", m.Entries[0].SourcePath)
+		assert.Empty(t, m.Entries[0].PairedPath)
+	})
+
+	t.Run("video", func(t *testing.T) {
+		generator := CreateGenerator("/test/backup", "gdrive:Photos", Config{LivePhotoMode: "video"})
+		m := generator.CreateManifest([]*types.Asset{newAsset()})
+
+		assert.Len(t, m.Entries, 1)
+		assert.Equal(t, "/test/backup/IMG_0001.MOV", m.Entries[0].SourcePath)
+		assert.Equal(t, int64(3000), m.Entries[0].FileSize)
+	})
+}
+
+func TestGenerator_CreateManifest_EditsModes(t *testing.T) {
+	creation := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	newAsset := func() *types.Asset {
+		return &types.Asset{
+			ID:             "1",
+			SourcePath:     "/test/backup/DCIM/100APPLE/IMG_0001.HEIC",
+			Filename:       "IMG_0001.HEIC",
+			Type:           types.AssetTypePhoto,
+			CreationDate:   creation,
+			FileSize:       1000,
+			MimeType:       "image/heif",
+			Flags:          types.AssetFlags{Edited: true},
+			EditedPath:     "/test/backup/PhotoData/Mutations/DCIM/100APPLE/IMG_0001/Adjustments/FullSizeRender.jpg",
+			EditedSize:     800,
+			EditedMimeType: "image/jpeg",
+		}
+	}
+
+	tests := []struct {
+		mode            string
+		expectedTargets []string
+	}{
+		{mode: "", expectedTargets: []string{"2024/03/18/photos/IMG_0001.HEIC"}},
+		{mode: "original", expectedTargets: []string{"2024/03/18/photos/IMG_0001.HEIC"}},
+		{mode: "edited", expectedTargets: []string{"2024/03/18/photos/IMG_0001_edited.jpg"}},
+		{mode: "both", expectedTargets: []string{"2024/03/18/photos/IMG_0001.HEIC", "2024/03/18/photos/IMG_0001_edited.jpg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			generator := CreateGenerator("/test/backup", "gdrive:Photos", Config{EditsMode: tt.mode})
+			m := generator.CreateManifest([]*types.Asset{newAsset()})
+
+			var targets []string
+			for _, entry := range m.Entries {
+				targets = append(targets, entry.TargetPath)
+				if entry.Edited {
+					assert.Equal(t, "image/jpeg", entry.MimeType)
+					assert.Equal(t, int64(800), entry.FileSize)
+				}
+			}
+			assert.Equal(t, tt.expectedTargets, targets)
+		})
+	}
+}
@@ -3,12 +3,14 @@ package photos
 import (
 	"database/sql"
 	"fmt"
+	"math"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/grantbirki/gh-photos/internal/logger"
+	"github.com/grantbirki/gh-photos/internal/plist"
 	"github.com/grantbirki/gh-photos/internal/types"
 	_ "modernc.org/sqlite"
 )
@@ -55,9 +57,65 @@ type SchemaInfo struct {
 	ModDateColumn      string
 	TrashedColumn      string
 	BurstColumn        string
+	BurstPickColumn    string
 	ScreenshotColumn   string
 	AdjustmentsColumn  string
+	FavoriteColumn     string
+	UUIDColumn         string
+	CloudGUIDColumn    string
+	LatitudeColumn     string
+	LongitudeColumn    string
 	TableName          string
+
+	// Media subtype columns (constant fallbacks when the schema lacks them)
+	DepthTypeColumn string
+	UTIColumn       string
+
+	// Album membership (empty when the schema has no album tables)
+	AlbumTable           string
+	AlbumTitleColumn     string
+	AlbumJoinTable       string
+	AlbumJoinAlbumColumn string
+	AlbumJoinAssetColumn string
+
+	// Named people and pets (empty when the schema has no face recognition tables)
+	PersonTable      string
+	PersonNameColumn string // SQL expression for the person's name
+	FaceTable        string
+	FaceAssetColumn  string
+	FacePersonColumn string
+
+	// Additional asset attributes (empty when the schema has no ZADDITIONALASSETATTRIBUTES table)
+	AttributesTable        string
+	AttributesAssetColumn  string
+	TimezoneOffsetColumn   string
+	TimezoneNameColumn     string
+	OriginalFilenameColumn string
+	ReverseLocationColumn  string
+}
+
+// ZKINDSUBTYPE values
+const (
+	kindSubtypePanorama        = 1
+	kindSubtypeLivePhoto       = 2
+	kindSubtypeSlomo           = 101
+	kindSubtypeTimelapse       = 102
+	kindSubtypeScreenRecording = 103
+)
+
+// mediaSubtype holds the per-asset columns that refine a photo or video into a subtype
+type mediaSubtype struct {
+	kindSubtype int64
+	depthType   int64  // non-zero for Portrait photos and Cinematic videos
+	uti         string // uniform type identifier, e.g. "com.adobe.raw-image"
+}
+
+// assetAttributes holds per-asset values read from ZADDITIONALASSETATTRIBUTES
+type assetAttributes struct {
+	timezoneOffset sql.NullInt64  // seconds east of UTC at capture time
+	timezoneName   sql.NullString // e.g. "GMT-0700" or "America/Los_Angeles"
+	originalName   sql.NullString // filename the asset was imported/captured with
+	reverseGeo     []byte         // keyed archive of the place Photos reverse geocoded the asset to
 }
 
 // detectSchema analyzes the Photos.sqlite schema to determine column names
@@ -180,6 +238,17 @@ func (d *Database) detectSchema() (*SchemaInfo, error) {
 		d.logger.Debug("No burst identifier column found, using NULL fallback")
 	}
 
+	// Determine burst pick type column (how each burst frame was chosen)
+	for _, col := range columns {
+		if col == "ZAVALANCHEPICKTYPE" {
+			info.BurstPickColumn = col
+		}
+	}
+	if info.BurstPickColumn == "" {
+		info.BurstPickColumn = "0" // Fallback to 0 (unknown) if not found
+		d.logger.Debug("No burst pick type column found, using 0 fallback")
+	}
+
 	// Determine screenshot column
 	for _, col := range columns {
 		switch col {
@@ -212,21 +281,428 @@ func (d *Database) detectSchema() (*SchemaInfo, error) {
 		d.logger.Debug("No adjustments column found, using 0 fallback")
 	}
 
+	// Determine favorite column
+	for _, col := range columns {
+		if col == "ZFAVORITE" {
+			info.FavoriteColumn = col
+		}
+	}
+	if info.FavoriteColumn == "" {
+		info.FavoriteColumn = "0" // Fallback to 0 (not a favorite) if not found
+		d.logger.Debug("No favorite column found, using 0 fallback")
+	}
+
+	// Determine media subtype columns (optional - used to detect Portrait, Cinematic and RAW assets)
+	for _, col := range columns {
+		switch col {
+		case "ZDEPTHTYPE":
+			info.DepthTypeColumn = col
+		case "ZUNIFORMTYPEIDENTIFIER":
+			info.UTIColumn = col
+		}
+	}
+	if info.DepthTypeColumn == "" {
+		info.DepthTypeColumn = "0" // Fallback to 0 (no depth effect) if not found
+		d.logger.Debug("No depth type column found, Portrait and Cinematic assets will not be detected")
+	}
+	if info.UTIColumn == "" {
+		info.UTIColumn = "NULL" // Fallback to NULL; RAW photos are then detected by extension only
+	}
+
+	// Determine the stable asset identifier columns (Z_PK is reassigned when the library is rebuilt)
+	for _, col := range columns {
+		switch col {
+		case "ZUUID":
+			info.UUIDColumn = col
+		case "ZCLOUDASSETGUID":
+			info.CloudGUIDColumn = col
+		}
+	}
+	if info.UUIDColumn == "" {
+		info.UUIDColumn = "NULL" // Fallback to NULL; assets are then identified by Z_PK only
+		d.logger.Debug("No UUID column found, using NULL fallback")
+	}
+	if info.CloudGUIDColumn == "" {
+		info.CloudGUIDColumn = "NULL" // Fallback to NULL for libraries that were never synced with iCloud
+	}
+
+	// Determine GPS coordinate columns
+	for _, col := range columns {
+		switch col {
+		case "ZLATITUDE":
+			info.LatitudeColumn = col
+		case "ZLONGITUDE":
+			info.LongitudeColumn = col
+		}
+	}
+	if info.LatitudeColumn == "" || info.LongitudeColumn == "" {
+		info.LatitudeColumn, info.LongitudeColumn = "NULL", "NULL" // Fallback to NULL (no location) if not found
+		d.logger.Debug("No location columns found, using NULL fallback")
+	}
+
 	if info.CreationDateColumn == "" {
 		return nil, fmt.Errorf("no suitable creation date column found in ZASSET table")
 	}
 
+	// Determine album tables (optional - older or stripped databases may not have them)
+	d.detectAlbumSchema(info)
+
+	// Determine face recognition tables (optional - older schemas name their columns differently)
+	d.detectPeopleSchema(info)
+
+	// Determine additional attributes table (optional - older schemas lack capture timezone columns)
+	d.detectAttributesSchema(info)
+
 	// Debug log the selected columns
 	d.logger.Debug("Selected creation date column", "column", info.CreationDateColumn)
 	d.logger.Debug("Selected modification date column", "column", info.ModDateColumn)
 	d.logger.Debug("Selected trashed column", "column", info.TrashedColumn)
 	d.logger.Debug("Selected burst column", "column", info.BurstColumn)
+	d.logger.Debug("Selected burst pick type column", "column", info.BurstPickColumn)
 	d.logger.Debug("Selected screenshot column", "column", info.ScreenshotColumn)
 	d.logger.Debug("Selected adjustments column", "column", info.AdjustmentsColumn)
+	d.logger.Debug("Selected favorite column", "column", info.FavoriteColumn)
+	d.logger.Debug("Selected media subtype columns", "depth_type", info.DepthTypeColumn, "uti", info.UTIColumn)
+	d.logger.Debug("Selected UUID column", "column", info.UUIDColumn)
+	d.logger.Debug("Selected iCloud asset GUID column", "column", info.CloudGUIDColumn)
+	d.logger.Debug("Selected location columns", "latitude", info.LatitudeColumn, "longitude", info.LongitudeColumn)
+	d.logger.Debug("Selected timezone columns", "table", info.AttributesTable, "offset", info.TimezoneOffsetColumn, "name", info.TimezoneNameColumn)
+	d.logger.Debug("Selected original filename column", "table", info.AttributesTable, "column", info.OriginalFilenameColumn)
+	d.logger.Debug("Selected album join table", "table", info.AlbumJoinTable, "album_column", info.AlbumJoinAlbumColumn, "asset_column", info.AlbumJoinAssetColumn)
+	d.logger.Debug("Selected face tables", "person_table", info.PersonTable, "face_table", info.FaceTable, "asset_column", info.FaceAssetColumn, "person_column", info.FacePersonColumn)
 
 	return info, nil
 }
 
+// detectAlbumSchema locates the album table and its many-to-many join table with ZASSET.
+// Core Data names the join table Z_<n>ASSETS with columns Z_<n>ALBUMS and Z_<m>ASSETS, where the
+// entity numbers differ between iOS versions, so they are discovered by probing rather than hard-coded.
+func (d *Database) detectAlbumSchema(info *SchemaInfo) {
+	albumColumns, err := d.tableColumns("ZGENERICALBUM")
+	if err != nil || len(albumColumns) == 0 {
+		d.logger.Debug("No ZGENERICALBUM table found, album membership will not be available")
+		return
+	}
+
+	for _, col := range albumColumns {
+		if col == "ZTITLE" {
+			info.AlbumTitleColumn = col
+		}
+	}
+	if info.AlbumTitleColumn == "" {
+		d.logger.Debug("ZGENERICALBUM has no ZTITLE column, album membership will not be available")
+		return
+	}
+
+	rows, err := d.db.Query("SELECT name FROM sqlite_master WHERE type='table' AND name LIKE 'Z\\_%ASSETS' ESCAPE '\\' ORDER BY name")
+	if err != nil {
+		d.logger.Debug("Failed to list album join table candidates", "error", err)
+		return
+	}
+	var candidates []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err == nil {
+			candidates = append(candidates, name)
+		}
+	}
+	rows.Close()
+
+	for _, table := range candidates {
+		columns, err := d.tableColumns(table)
+		if err != nil {
+			continue
+		}
+
+		var albumCol, assetCol string
+		for _, col := range columns {
+			switch {
+			case strings.HasPrefix(col, "Z_FOK_"):
+				// Ordering column, not a foreign key
+			case strings.HasPrefix(col, "Z_") && strings.HasSuffix(col, "ALBUMS"):
+				albumCol = col
+			case strings.HasPrefix(col, "Z_") && strings.HasSuffix(col, "ASSETS"):
+				assetCol = col
+			}
+		}
+
+		if albumCol != "" && assetCol != "" {
+			info.AlbumTable = "ZGENERICALBUM"
+			info.AlbumJoinTable = table
+			info.AlbumJoinAlbumColumn = albumCol
+			info.AlbumJoinAssetColumn = assetCol
+			return
+		}
+	}
+
+	d.logger.Debug("No album join table found, album membership will not be available", "candidates", strings.Join(candidates, ", "))
+}
+
+// detectPeopleSchema locates the named people (ZPERSON) and the detected faces (ZDETECTEDFACE) linking
+// them to assets. iOS 16 renamed the face columns ZASSET and ZPERSON to ZASSETFORFACE and ZPERSONFORFACE;
+// both spellings are probed. Pets are recorded in ZPERSON as well.
+func (d *Database) detectPeopleSchema(info *SchemaInfo) {
+	personColumns, err := d.tableColumns("ZPERSON")
+	if err != nil || len(personColumns) == 0 {
+		d.logger.Debug("No ZPERSON table found, people will not be available")
+		return
+	}
+
+	var displayName, fullName bool
+	for _, col := range personColumns {
+		switch col {
+		case "ZDISPLAYNAME":
+			displayName = true
+		case "ZFULLNAME":
+			fullName = true
+		}
+	}
+	switch {
+	case displayName && fullName:
+		info.PersonNameColumn = "COALESCE(NULLIF(p.ZDISPLAYNAME, ''), p.ZFULLNAME)" // name shown in Photos, else the contact name
+	case displayName:
+		info.PersonNameColumn = "p.ZDISPLAYNAME"
+	case fullName:
+		info.PersonNameColumn = "p.ZFULLNAME"
+	default:
+		d.logger.Debug("ZPERSON has no name column, people will not be available")
+		return
+	}
+
+	faceColumns, err := d.tableColumns("ZDETECTEDFACE")
+	if err != nil || len(faceColumns) == 0 {
+		d.logger.Debug("No ZDETECTEDFACE table found, people will not be available")
+		info.PersonNameColumn = ""
+		return
+	}
+
+	var assetCol, personCol string
+	for _, col := range faceColumns {
+		switch col {
+		case "ZASSETFORFACE":
+			assetCol = col
+		case "ZASSET":
+			if assetCol == "" {
+				assetCol = col
+			}
+		case "ZPERSONFORFACE":
+			personCol = col
+		case "ZPERSON":
+			if personCol == "" {
+				personCol = col
+			}
+		}
+	}
+	if assetCol == "" || personCol == "" {
+		d.logger.Debug("ZDETECTEDFACE has no asset or person column, people will not be available")
+		info.PersonNameColumn = ""
+		return
+	}
+
+	info.PersonTable = "ZPERSON"
+	info.FaceTable = "ZDETECTEDFACE"
+	info.FaceAssetColumn = assetCol
+	info.FacePersonColumn = personCol
+}
+
+// detectAttributesSchema locates ZADDITIONALASSETATTRIBUTES and the per-asset columns read from it
+func (d *Database) detectAttributesSchema(info *SchemaInfo) {
+	columns, err := d.tableColumns("ZADDITIONALASSETATTRIBUTES")
+	if err != nil || len(columns) == 0 {
+		d.logger.Debug("No ZADDITIONALASSETATTRIBUTES table found, capture timezones, original filenames and geocoded places will not be available")
+		return
+	}
+
+	for _, col := range columns {
+		switch col {
+		case "ZASSET":
+			info.AttributesAssetColumn = col
+		case "ZTIMEZONEOFFSET":
+			info.TimezoneOffsetColumn = col
+		case "ZTIMEZONENAME":
+			info.TimezoneNameColumn = col
+		case "ZORIGINALFILENAME":
+			info.OriginalFilenameColumn = col
+		case "ZREVERSELOCATIONDATA":
+			info.ReverseLocationColumn = col
+		}
+	}
+	if info.AttributesAssetColumn == "" {
+		d.logger.Debug("ZADDITIONALASSETATTRIBUTES has no ZASSET column, capture timezones, original filenames and geocoded places will not be available")
+		return
+	}
+
+	info.AttributesTable = "ZADDITIONALASSETATTRIBUTES"
+}
+
+// tableColumns returns the column names of the given table (empty if the table doesn't exist)
+func (d *Database) tableColumns(table string) ([]string, error) {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s table info: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var cid int
+		var name, dataType string
+		var notNull, pk int
+		var defaultValue sql.NullString
+
+		if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan column info: %w", err)
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, rows.Err()
+}
+
+// getAlbumMemberships returns album titles keyed by asset primary key
+func (d *Database) getAlbumMemberships(schema *SchemaInfo) (map[int64][]string, error) {
+	memberships := make(map[int64][]string)
+	if schema.AlbumJoinTable == "" {
+		return memberships, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT j.%s, a.%s
+		FROM %s j
+		JOIN %s a ON a.Z_PK = j.%s
+		WHERE a.%s IS NOT NULL AND a.%s != ''
+		ORDER BY a.%s ASC
+	`, schema.AlbumJoinAssetColumn, schema.AlbumTitleColumn,
+		schema.AlbumJoinTable,
+		schema.AlbumTable, schema.AlbumJoinAlbumColumn,
+		schema.AlbumTitleColumn, schema.AlbumTitleColumn,
+		schema.AlbumTitleColumn)
+
+	d.logger.Debug("Generated album membership query", "query", strings.TrimSpace(query))
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query album memberships: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assetID int64
+		var title string
+		if err := rows.Scan(&assetID, &title); err != nil {
+			return nil, fmt.Errorf("failed to scan album membership: %w", err)
+		}
+
+		// The same album can be linked more than once (e.g. shared/duplicated rows), keep names unique
+		duplicate := false
+		for _, existing := range memberships[assetID] {
+			if existing == title {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			memberships[assetID] = append(memberships[assetID], title)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating album memberships: %w", err)
+	}
+
+	return memberships, nil
+}
+
+// getPeople returns the names of the people and pets recognized in each asset, keyed by asset primary key.
+// Unnamed faces are left out.
+func (d *Database) getPeople(schema *SchemaInfo) (map[int64][]string, error) {
+	people := make(map[int64][]string)
+	if schema.FaceTable == "" {
+		return people, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT f.%s, %s AS name
+		FROM %s f
+		JOIN %s p ON p.Z_PK = f.%s
+		WHERE f.%s IS NOT NULL AND name IS NOT NULL AND name != ''
+		ORDER BY name ASC
+	`, schema.FaceAssetColumn, schema.PersonNameColumn,
+		schema.FaceTable,
+		schema.PersonTable, schema.FacePersonColumn,
+		schema.FaceAssetColumn)
+
+	d.logger.Debug("Generated people query", "query", strings.TrimSpace(query))
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query people: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assetID int64
+		var name string
+		if err := rows.Scan(&assetID, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan person: %w", err)
+		}
+		people[assetID] = append(people[assetID], name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating people: %w", err)
+	}
+
+	return people, nil
+}
+
+// getAssetAttributes returns additional asset attributes keyed by asset primary key
+func (d *Database) getAssetAttributes(schema *SchemaInfo) (map[int64]assetAttributes, error) {
+	attributes := make(map[int64]assetAttributes)
+	if schema.AttributesTable == "" {
+		return attributes, nil
+	}
+
+	// Missing columns are selected as NULL so older schemas still work
+	orNull := func(column string) string {
+		if column == "" {
+			return "NULL"
+		}
+		return column
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s, %s, %s, %s
+		FROM %s
+		WHERE %s IS NOT NULL
+	`, schema.AttributesAssetColumn, orNull(schema.TimezoneOffsetColumn), orNull(schema.TimezoneNameColumn), orNull(schema.OriginalFilenameColumn), orNull(schema.ReverseLocationColumn),
+		schema.AttributesTable,
+		schema.AttributesAssetColumn)
+
+	d.logger.Debug("Generated asset attributes query", "query", strings.TrimSpace(query))
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query asset attributes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assetID int64
+		var attrs assetAttributes
+		if err := rows.Scan(&assetID, &attrs.timezoneOffset, &attrs.timezoneName, &attrs.originalName, &attrs.reverseGeo); err != nil {
+			return nil, fmt.Errorf("failed to scan asset attributes: %w", err)
+		}
+		attributes[assetID] = attrs
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating asset attributes: %w", err)
+	}
+
+	return attributes, nil
+}
+
 // GetAssets retrieves all assets from the Photos database
 func (d *Database) GetAssets(dcimPath string) ([]*types.Asset, error) {
 	// Detect the schema to use appropriate column names
@@ -248,15 +724,44 @@ func (d *Database) GetAssets(dcimPath string) ([]*types.Asset, error) {
 			ZKINDSUBTYPE,
 			%s,
 			%s,
+			%s,
+			%s,
+			%s,
+			%s,
+			%s,
+			%s,
+			%s,
+			%s,
 			%s
 		FROM %s 
 		WHERE ZFILENAME IS NOT NULL
 		ORDER BY %s ASC
-	`, schema.CreationDateColumn, schema.ModDateColumn, schema.TrashedColumn, schema.BurstColumn, schema.ScreenshotColumn, schema.AdjustmentsColumn, schema.TableName, schema.CreationDateColumn)
+	`, schema.CreationDateColumn, schema.ModDateColumn, schema.TrashedColumn, schema.BurstColumn, schema.BurstPickColumn, schema.ScreenshotColumn, schema.AdjustmentsColumn, schema.FavoriteColumn, schema.DepthTypeColumn, schema.UTIColumn, schema.UUIDColumn, schema.CloudGUIDColumn, schema.LatitudeColumn, schema.LongitudeColumn, schema.TableName, schema.CreationDateColumn)
 
 	// Debug log the generated query
 	d.logger.Debug("Generated Photos.sqlite query", "query", strings.TrimSpace(query))
 
+	// Load album memberships up front; failures only lose album information
+	albumMemberships, err := d.getAlbumMemberships(schema)
+	if err != nil {
+		d.logger.Warn("Failed to read album memberships, continuing without album information", "error", err)
+		albumMemberships = make(map[int64][]string)
+	}
+
+	// Load recognized people; failures only lose people information
+	people, err := d.getPeople(schema)
+	if err != nil {
+		d.logger.Warn("Failed to read people, continuing without people information", "error", err)
+		people = make(map[int64][]string)
+	}
+
+	// Load capture timezones, original filenames and places; failures leave creation dates in UTC
+	attributes, err := d.getAssetAttributes(schema)
+	if err != nil {
+		d.logger.Warn("Failed to read additional asset attributes, continuing with UTC creation dates", "error", err)
+		attributes = make(map[int64]assetAttributes)
+	}
+
 	rows, err := d.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query assets: %w", err)
@@ -281,8 +786,16 @@ func (d *Database) GetAssets(dcimPath string) ([]*types.Asset, error) {
 			trashed          sql.NullInt64
 			kindSubtype      sql.NullInt64
 			burstID          sql.NullString
+			burstPick        sql.NullInt64
 			isScreenshot     sql.NullInt64
 			hasAdjustments   sql.NullInt64
+			favorite         sql.NullInt64
+			depthType        sql.NullInt64
+			uti              sql.NullString
+			uuid             sql.NullString
+			cloudGUID        sql.NullString
+			latitude         sql.NullFloat64
+			longitude        sql.NullFloat64
 		)
 
 		err := rows.Scan(
@@ -295,8 +808,16 @@ func (d *Database) GetAssets(dcimPath string) ([]*types.Asset, error) {
 			&trashed,
 			&kindSubtype,
 			&burstID,
+			&burstPick,
 			&isScreenshot,
 			&hasAdjustments,
+			&favorite,
+			&depthType,
+			&uti,
+			&uuid,
+			&cloudGUID,
+			&latitude,
+			&longitude,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row (using schema with creation date column %s): %w", schema.CreationDateColumn, err)
@@ -318,30 +839,59 @@ func (d *Database) GetAssets(dcimPath string) ([]*types.Asset, error) {
 		// Build the source path
 		sourcePath := filepath.Join(dcimPath, directory.String, filename.String)
 
+		subtype := mediaSubtype{
+			kindSubtype: kindSubtype.Int64,
+			depthType:   depthType.Int64,
+			uti:         uti.String,
+		}
+
 		// Create asset flags
 		flags := types.AssetFlags{
 			Hidden:          hidden.Valid && hidden.Int64 == 1,
 			RecentlyDeleted: trashed.Valid && trashed.Int64 == 1,
 			Screenshot:      isScreenshot.Valid && isScreenshot.Int64 == 1,
 			Burst:           burstID.Valid && burstID.String != "",
-			LivePhoto:       kindSubtype.Valid && kindSubtype.Int64 == 2, // Live Photo subtype
+			LivePhoto:       subtype.kindSubtype == kindSubtypeLivePhoto,
+			Edited:          hasAdjustments.Valid && hasAdjustments.Int64 == 1,
+			Favorite:        favorite.Valid && favorite.Int64 == 1,
 		}
 
 		if flags.Burst && burstID.Valid {
 			flags.BurstID = &burstID.String
+			flags.BurstPick = types.BurstPick(burstPick.Int64)
 		}
 
 		// Classify asset type
-		assetType := classifyAsset(filename.String, flags)
+		assetType := classifyAsset(filename.String, flags, subtype)
 
 		asset := &types.Asset{
-			ID:           strconv.FormatInt(id, 10),
-			SourcePath:   sourcePath,
-			Filename:     filename.String,
-			Type:         assetType,
-			CreationDate: createdAt,
-			ModifiedDate: modifiedAt,
-			Flags:        flags,
+			ID:             strconv.FormatInt(id, 10),
+			UUID:           uuid.String,
+			CloudAssetGUID: cloudGUID.String,
+			SourcePath:     sourcePath,
+			Filename:       filename.String,
+			Type:           assetType,
+			CreationDate:   createdAt,
+			ModifiedDate:   modifiedAt,
+			Flags:          flags,
+			Albums:         albumMemberships[id],
+			People:         people[id],
+			Location:       location(latitude, longitude),
+		}
+
+		if attrs, ok := attributes[id]; ok {
+			// Express the creation date in the timezone it was captured in
+			if attrs.timezoneOffset.Valid {
+				offset := int(attrs.timezoneOffset.Int64)
+				asset.SetCaptureTimezone(attrs.timezoneName.String, offset)
+			}
+			if attrs.originalName.Valid {
+				asset.OriginalFilename = attrs.originalName.String
+			}
+			// Photos' own reverse geocoding takes precedence over the offline gazetteer
+			if asset.Location != nil && len(attrs.reverseGeo) > 0 {
+				asset.City, asset.Country = reverseGeocodedPlace(attrs.reverseGeo)
+			}
 		}
 
 		// Optimize file path resolution - avoid expensive Glob operations
@@ -361,18 +911,57 @@ func (d *Database) GetAssets(dcimPath string) ([]*types.Asset, error) {
 	return assets, nil
 }
 
+// reverseGeocodedPlace returns the city and country from the PLRevGeoLocationInfo archive Photos
+// stores in ZREVERSELOCATIONDATA. Either is empty when Photos didn't record it.
+func reverseGeocodedPlace(data []byte) (city, country string) {
+	archive, err := plist.DecodeKeyedArchive(data)
+	if err != nil {
+		return "", ""
+	}
+	address, ok := archive.Resolve(archive.Root()["postalAddress"]).(map[string]any)
+	if !ok {
+		return "", ""
+	}
+	city, _ = archive.String(address, "_city")
+	country, _ = archive.String(address, "_country")
+	return strings.TrimSpace(city), strings.TrimSpace(country)
+}
+
+// location returns the GPS coordinates of an asset, or nil when it has none.
+// Photos stores -180, -180 rather than NULL for assets without a location.
+func location(latitude, longitude sql.NullFloat64) *types.Location {
+	if !latitude.Valid || !longitude.Valid {
+		return nil
+	}
+	lat, lon := latitude.Float64, longitude.Float64
+	if lat == -180 && lon == -180 {
+		return nil
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil
+	}
+	return &types.Location{Latitude: lat, Longitude: lon}
+}
+
 // coreDataTimeToGoTime converts Core Data timestamp to Go time
-// Core Data stores time as seconds since 2001-01-01 00:00:00 UTC
+// Core Data stores time as seconds since 2001-01-01 00:00:00 UTC, with sub-second precision in the fraction
 func coreDataTimeToGoTime(seconds float64) time.Time {
 	coreDataEpoch := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
-	return coreDataEpoch.Add(time.Duration(seconds) * time.Second)
+	whole := math.Floor(seconds)
+	nanos := math.Round((seconds - whole) * float64(time.Second))
+	return coreDataEpoch.Add(time.Duration(whole)*time.Second + time.Duration(nanos))
 }
 
 // classifyAsset determines the asset type based on filename and flags
-func classifyAsset(filename string, flags types.AssetFlags) types.AssetType {
+// and the media subtype columns. Subtypes of photos and videos refine the extension-based
+// classification; screenshots, screen recordings, Live Photos and bursts take precedence.
+func classifyAsset(filename string, flags types.AssetFlags, subtype mediaSubtype) types.AssetType {
 	if flags.Screenshot {
 		return types.AssetTypeScreenshot
 	}
+	if subtype.kindSubtype == kindSubtypeScreenRecording {
+		return types.AssetTypeScreenRecording
+	}
 	if flags.LivePhoto {
 		return types.AssetTypeLivePhoto
 	}
@@ -380,8 +969,42 @@ func classifyAsset(filename string, flags types.AssetFlags) types.AssetType {
 		return types.AssetTypeBurst
 	}
 
-	// Use extension-based classification for regular photos/videos
-	return types.ClassifyByExtension(filename)
+	// Use extension-based classification for regular photos/videos, then refine it
+	if types.ClassifyByExtension(filename) == types.AssetTypeVideo {
+		switch {
+		case subtype.kindSubtype == kindSubtypeSlomo:
+			return types.AssetTypeSlomo
+		case subtype.kindSubtype == kindSubtypeTimelapse:
+			return types.AssetTypeTimelapse
+		case subtype.depthType != 0:
+			return types.AssetTypeCinematic
+		}
+		return types.AssetTypeVideo
+	}
+
+	switch {
+	case subtype.depthType != 0:
+		return types.AssetTypePortrait
+	case subtype.kindSubtype == kindSubtypePanorama:
+		return types.AssetTypePanorama
+	case isRawImage(filename, subtype.uti):
+		return types.AssetTypeRaw
+	}
+	return types.AssetTypePhoto
+}
+
+// rawExtensions are the RAW formats recognized when the uniform type identifier is unavailable
+var rawExtensions = map[string]bool{
+	".dng": true, ".cr2": true, ".cr3": true, ".nef": true, ".arw": true, ".raf": true, ".orf": true, ".rw2": true,
+}
+
+// isRawImage reports whether a photo is RAW (including Apple ProRAW, which is stored as DNG)
+func isRawImage(filename, uti string) bool {
+	// Camera RAW identifiers all end in "raw-image", e.g. com.adobe.raw-image or com.canon.cr2-raw-image
+	if strings.HasSuffix(strings.ToLower(uti), "raw-image") {
+		return true
+	}
+	return rawExtensions[strings.ToLower(filepath.Ext(filename))]
 }
 
 // ValidateDatabase checks if the given path contains a valid Photos.sqlite database
@@ -34,6 +34,11 @@ func TestCoreDataTimeToGoTime(t *testing.T) {
 			seconds:  3600, // 60 * 60
 			expected: time.Date(2001, 1, 1, 1, 0, 0, 0, time.UTC),
 		},
+		{
+			name:     "sub-second precision is kept",
+			seconds:  3600.25,
+			expected: time.Date(2001, 1, 1, 1, 0, 0, 250000000, time.UTC),
+		},
 	}
 
 	for _, tt := range tests {
@@ -49,6 +54,7 @@ func TestClassifyAsset(t *testing.T) {
 		name     string
 		filename string
 		flags    types.AssetFlags
+		subtype  mediaSubtype
 		expected types.AssetType
 	}{
 		{
@@ -81,11 +87,66 @@ func TestClassifyAsset(t *testing.T) {
 			flags:    types.AssetFlags{},
 			expected: types.AssetTypeVideo,
 		},
+		{
+			name:     "slo-mo video",
+			filename: "IMG_005.MOV",
+			subtype:  mediaSubtype{kindSubtype: kindSubtypeSlomo},
+			expected: types.AssetTypeSlomo,
+		},
+		{
+			name:     "time-lapse video",
+			filename: "IMG_006.MOV",
+			subtype:  mediaSubtype{kindSubtype: kindSubtypeTimelapse},
+			expected: types.AssetTypeTimelapse,
+		},
+		{
+			name:     "cinematic video",
+			filename: "IMG_007.MOV",
+			subtype:  mediaSubtype{depthType: 1},
+			expected: types.AssetTypeCinematic,
+		},
+		{
+			name:     "screen recording takes precedence over live photo",
+			filename: "RPReplay_Final.MP4",
+			flags:    types.AssetFlags{LivePhoto: true},
+			subtype:  mediaSubtype{kindSubtype: kindSubtypeScreenRecording},
+			expected: types.AssetTypeScreenRecording,
+		},
+		{
+			name:     "portrait photo",
+			filename: "IMG_008.HEIC",
+			subtype:  mediaSubtype{depthType: 2},
+			expected: types.AssetTypePortrait,
+		},
+		{
+			name:     "live photo takes precedence over portrait",
+			filename: "IMG_009.HEIC",
+			flags:    types.AssetFlags{LivePhoto: true},
+			subtype:  mediaSubtype{depthType: 2},
+			expected: types.AssetTypeLivePhoto,
+		},
+		{
+			name:     "panorama",
+			filename: "IMG_010.HEIC",
+			subtype:  mediaSubtype{kindSubtype: kindSubtypePanorama},
+			expected: types.AssetTypePanorama,
+		},
+		{
+			name:     "ProRAW by uniform type identifier",
+			filename: "IMG_011.DNG",
+			subtype:  mediaSubtype{uti: "com.adobe.raw-image"},
+			expected: types.AssetTypeRaw,
+		},
+		{
+			name:     "camera RAW by extension",
+			filename: "DSC_0001.NEF",
+			expected: types.AssetTypeRaw,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := classifyAsset(tt.filename, tt.flags)
+			result := classifyAsset(tt.filename, tt.flags, tt.subtype)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -385,9 +446,498 @@ func TestGetAssets_SchemaAdaptive(t *testing.T) {
 
 	assert.Equal(t, "IMG_001.HEIC", assets[0].Filename)
 	assert.Equal(t, "1", assets[0].ID)
+	assert.Empty(t, assets[0].UUID, "schemas without ZUUID identify assets by Z_PK")
+	assert.Equal(t, "1", assets[0].StableID())
 	assert.False(t, assets[0].CreationDate.IsZero())
+	assert.Nil(t, assets[0].Location, "schemas without ZLATITUDE have no locations")
 
 	// Verify the date conversion worked correctly
 	expectedTime := time.Date(2001, 1, 2, 0, 0, 0, 0, time.UTC)
 	assert.Equal(t, expectedTime, assets[0].CreationDate)
 }
+
+func TestGetAssets_UUID(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "Photos.sqlite"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE ZASSET (
+			Z_PK INTEGER PRIMARY KEY,
+			ZUUID TEXT,
+			ZCLOUDASSETGUID TEXT,
+			ZFILENAME TEXT,
+			ZDIRECTORY TEXT,
+			ZDATECREATED REAL,
+			ZHIDDEN INTEGER,
+			ZTRASHED INTEGER,
+			ZKINDSUBTYPE INTEGER
+		);
+		INSERT INTO ZASSET VALUES (7, '5A1F2C3D-0000-4000-8000-00000000000A', 'AZxQ3f1cE9Lr', 'IMG_0007.HEIC', 'DCIM/100APPLE', 86400, 0, 0, 0);
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	photosDB := &Database{db: db, logger: logger.New(logger.Config{Level: logger.LevelError, Output: io.Discard})}
+	assets, err := photosDB.GetAssets("/fake/dcim/path")
+	if !assert.NoError(t, err) || !assert.Len(t, assets, 1) {
+		return
+	}
+
+	assert.Equal(t, "7", assets[0].ID)
+	assert.Equal(t, "5A1F2C3D-0000-4000-8000-00000000000A", assets[0].UUID)
+	assert.Equal(t, "AZxQ3f1cE9Lr", assets[0].CloudAssetGUID)
+	assert.Equal(t, assets[0].UUID, assets[0].StableID())
+}
+
+func TestGetAssets_Favorite(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "Photos.sqlite"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE ZASSET (
+			Z_PK INTEGER PRIMARY KEY,
+			ZFILENAME TEXT,
+			ZDIRECTORY TEXT,
+			ZDATECREATED REAL,
+			ZHIDDEN INTEGER,
+			ZTRASHED INTEGER,
+			ZKINDSUBTYPE INTEGER,
+			ZFAVORITE INTEGER
+		);
+		INSERT INTO ZASSET VALUES (1, 'IMG_0001.HEIC', 'DCIM/100APPLE', 1, 0, 0, 0, 1);
+		INSERT INTO ZASSET VALUES (2, 'IMG_0002.HEIC', 'DCIM/100APPLE', 2, 0, 0, 0, 0);
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	photosDB := &Database{db: db, logger: logger.New(logger.Config{Level: logger.LevelError, Output: io.Discard})}
+	assets, err := photosDB.GetAssets("/fake/dcim/path")
+	if !assert.NoError(t, err) || !assert.Len(t, assets, 2) {
+		return
+	}
+
+	assert.True(t, assets[0].Flags.Favorite)
+	assert.False(t, assets[1].Flags.Favorite)
+}
+
+func TestGetAssets_MediaSubtypes(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "Photos.sqlite"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE ZASSET (
+			Z_PK INTEGER PRIMARY KEY,
+			ZFILENAME TEXT,
+			ZDIRECTORY TEXT,
+			ZDATECREATED REAL,
+			ZHIDDEN INTEGER,
+			ZTRASHED INTEGER,
+			ZKINDSUBTYPE INTEGER,
+			ZPLAYBACKSTYLE INTEGER,
+			ZDEPTHTYPE INTEGER,
+			ZUNIFORMTYPEIDENTIFIER TEXT
+		);
+		INSERT INTO ZASSET VALUES (1, 'IMG_0001.MOV', 'DCIM/100APPLE', 1, 0, 0, 101, 4, 0, 'com.apple.quicktime-movie');
+		INSERT INTO ZASSET VALUES (2, 'IMG_0002.HEIC', 'DCIM/100APPLE', 2, 0, 0, 0, 1, 2, 'public.heic');
+		INSERT INTO ZASSET VALUES (3, 'IMG_0003.DNG', 'DCIM/100APPLE', 3, 0, 0, 0, 1, 0, 'com.adobe.raw-image');
+		INSERT INTO ZASSET VALUES (4, 'IMG_0004.HEIC', 'DCIM/100APPLE', 4, 0, 0, 0, 3, 0, 'public.heic');
+		INSERT INTO ZASSET VALUES (5, 'IMG_0005.HEIC', 'DCIM/100APPLE', 5, 0, 0, 0, 1, 0, 'public.heic');
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	photosDB := &Database{db: db, logger: logger.New(logger.Config{Level: logger.LevelError, Output: io.Discard})}
+	assets, err := photosDB.GetAssets("/fake/dcim/path")
+	if !assert.NoError(t, err) || !assert.Len(t, assets, 5) {
+		return
+	}
+
+	assert.Equal(t, types.AssetTypeSlomo, assets[0].Type)
+	assert.Equal(t, types.AssetTypePortrait, assets[1].Type)
+	assert.Equal(t, types.AssetTypeRaw, assets[2].Type)
+	// ZPLAYBACKSTYLE alone doesn't make a Live Photo, so photos synced before keep their folder
+	assert.Equal(t, types.AssetTypePhoto, assets[3].Type)
+	assert.False(t, assets[3].Flags.LivePhoto)
+	assert.Equal(t, types.AssetTypePhoto, assets[4].Type)
+}
+
+func TestGetAssets_BurstPickType(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "Photos.sqlite"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE ZASSET (
+			Z_PK INTEGER PRIMARY KEY,
+			ZFILENAME TEXT,
+			ZDIRECTORY TEXT,
+			ZDATECREATED REAL,
+			ZHIDDEN INTEGER,
+			ZTRASHED INTEGER,
+			ZKINDSUBTYPE INTEGER,
+			ZAVALANCHEUUID TEXT,
+			ZAVALANCHEPICKTYPE INTEGER
+		);
+		INSERT INTO ZASSET VALUES (1, 'IMG_0001.JPG', 'DCIM/100APPLE', 1, 0, 0, 0, 'BURST-1', 18);
+		INSERT INTO ZASSET VALUES (2, 'IMG_0002.JPG', 'DCIM/100APPLE', 2, 0, 0, 0, 'BURST-1', 8);
+		INSERT INTO ZASSET VALUES (3, 'IMG_0003.JPG', 'DCIM/100APPLE', 3, 0, 0, 0, NULL, 0);
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	photosDB := &Database{db: db, logger: logger.New(logger.Config{Level: logger.LevelError, Output: io.Discard})}
+	assets, err := photosDB.GetAssets("/fake/dcim/path")
+	if !assert.NoError(t, err) || !assert.Len(t, assets, 3) {
+		return
+	}
+
+	assert.Equal(t, types.AssetTypeBurst, assets[0].Type)
+	assert.True(t, assets[0].Flags.BurstPick.Has(types.BurstPickKey))
+	assert.True(t, assets[0].Flags.BurstPick.Has(types.BurstPickNotSelected))
+	assert.True(t, assets[1].Flags.BurstPick.Has(types.BurstPickUser))
+	assert.False(t, assets[1].Flags.BurstPick.Has(types.BurstPickKey))
+	assert.Nil(t, assets[2].Flags.BurstID)
+	assert.Zero(t, assets[2].Flags.BurstPick)
+}
+
+func TestGetAssets_Location(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "Photos.sqlite"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	// Photos stores -180, -180 for assets without a location
+	_, err = db.Exec(`
+		CREATE TABLE ZASSET (
+			Z_PK INTEGER PRIMARY KEY,
+			ZFILENAME TEXT,
+			ZDIRECTORY TEXT,
+			ZDATECREATED REAL,
+			ZHIDDEN INTEGER,
+			ZTRASHED INTEGER,
+			ZKINDSUBTYPE INTEGER,
+			ZLATITUDE FLOAT,
+			ZLONGITUDE FLOAT
+		);
+		INSERT INTO ZASSET VALUES (1, 'IMG_0001.HEIC', 'DCIM/100APPLE', 1, 0, 0, 0, 39.7392, -104.9903);
+		INSERT INTO ZASSET VALUES (2, 'IMG_0002.HEIC', 'DCIM/100APPLE', 2, 0, 0, 0, -180, -180);
+		INSERT INTO ZASSET VALUES (3, 'IMG_0003.HEIC', 'DCIM/100APPLE', 3, 0, 0, 0, NULL, NULL);
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	photosDB := &Database{db: db, logger: logger.New(logger.Config{Level: logger.LevelError, Output: io.Discard})}
+	assets, err := photosDB.GetAssets("/fake/dcim/path")
+	if !assert.NoError(t, err) || !assert.Len(t, assets, 3) {
+		return
+	}
+
+	if assert.NotNil(t, assets[0].Location) {
+		assert.Equal(t, 39.7392, assets[0].Location.Latitude)
+		assert.Equal(t, -104.9903, assets[0].Location.Longitude)
+	}
+	assert.Nil(t, assets[1].Location)
+	assert.Nil(t, assets[2].Location)
+}
+
+func TestGetAssets_People(t *testing.T) {
+	tests := []struct {
+		name     string
+		tables   string
+		expected map[string][]string
+	}{
+		{
+			name: "iOS 16+ face columns",
+			tables: `
+				CREATE TABLE ZPERSON (Z_PK INTEGER PRIMARY KEY, ZDISPLAYNAME TEXT, ZFULLNAME TEXT);
+				CREATE TABLE ZDETECTEDFACE (Z_PK INTEGER PRIMARY KEY, ZASSETFORFACE INTEGER, ZPERSONFORFACE INTEGER);
+				INSERT INTO ZPERSON VALUES (1, 'Grandma', 'Jane Smith');
+				INSERT INTO ZPERSON VALUES (2, '', 'Biscuit');
+				INSERT INTO ZPERSON VALUES (3, NULL, NULL);
+				INSERT INTO ZDETECTEDFACE VALUES (1, 1, 1);
+				INSERT INTO ZDETECTEDFACE VALUES (2, 1, 2);
+				INSERT INTO ZDETECTEDFACE VALUES (3, 1, 1);
+				INSERT INTO ZDETECTEDFACE VALUES (4, 2, 3);
+			`,
+			expected: map[string][]string{"IMG_001.HEIC": {"Biscuit", "Grandma"}},
+		},
+		{
+			name: "older face columns",
+			tables: `
+				CREATE TABLE ZPERSON (Z_PK INTEGER PRIMARY KEY, ZFULLNAME TEXT);
+				CREATE TABLE ZDETECTEDFACE (Z_PK INTEGER PRIMARY KEY, ZASSET INTEGER, ZPERSON INTEGER);
+				INSERT INTO ZPERSON VALUES (1, 'Grandma');
+				INSERT INTO ZDETECTEDFACE VALUES (1, 2, 1);
+			`,
+			expected: map[string][]string{"IMG_002.HEIC": {"Grandma"}},
+		},
+		{
+			name:     "no face tables",
+			tables:   `CREATE TABLE ZPERSON (Z_PK INTEGER PRIMARY KEY, ZFULLNAME TEXT);`,
+			expected: map[string][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "Photos.sqlite"))
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer db.Close()
+
+			_, err = db.Exec(`
+				CREATE TABLE ZASSET (
+					Z_PK INTEGER PRIMARY KEY,
+					ZFILENAME TEXT,
+					ZDIRECTORY TEXT,
+					ZDATECREATED REAL,
+					ZHIDDEN INTEGER,
+					ZTRASHED INTEGER,
+					ZKINDSUBTYPE INTEGER
+				);
+				INSERT INTO ZASSET VALUES (1, 'IMG_001.HEIC', '100APPLE', 86400, 0, 0, 0);
+				INSERT INTO ZASSET VALUES (2, 'IMG_002.HEIC', '100APPLE', 86400, 0, 0, 0);
+			` + tt.tables)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			photosDB := &Database{db: db, logger: logger.New(logger.Config{Level: logger.LevelError, Output: io.Discard})}
+			assets, err := photosDB.GetAssets("/fake/dcim/path")
+			if !assert.NoError(t, err) || !assert.Len(t, assets, 2) {
+				return
+			}
+
+			people := make(map[string][]string)
+			for _, asset := range assets {
+				if len(asset.People) > 0 {
+					people[asset.Filename] = asset.People
+				}
+			}
+			assert.Equal(t, tt.expected, people)
+		})
+	}
+}
+
+func TestGetAssets_AlbumMemberships(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "Photos.sqlite")
+
+	db, err := sql.Open("sqlite", dbPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	// Core Data join table naming as seen on iOS 16+ (entity numbers vary by version)
+	_, err = db.Exec(`
+		CREATE TABLE ZASSET (
+			Z_PK INTEGER PRIMARY KEY,
+			ZFILENAME TEXT,
+			ZDIRECTORY TEXT,
+			ZDATECREATED REAL,
+			ZHIDDEN INTEGER,
+			ZTRASHED INTEGER,
+			ZKINDSUBTYPE INTEGER
+		);
+		CREATE TABLE ZGENERICALBUM (
+			Z_PK INTEGER PRIMARY KEY,
+			ZKIND INTEGER,
+			ZTITLE TEXT
+		);
+		CREATE TABLE Z_28ASSETS (
+			Z_28ALBUMS INTEGER,
+			Z_3ASSETS INTEGER,
+			Z_FOK_3ASSETS INTEGER
+		);
+		INSERT INTO ZASSET VALUES (1, 'IMG_001.HEIC', '100APPLE', 86400, 0, 0, 0);
+		INSERT INTO ZASSET VALUES (2, 'IMG_002.HEIC', '100APPLE', 86400, 0, 0, 0);
+		INSERT INTO ZASSET VALUES (3, 'IMG_003.HEIC', '100APPLE', 86400, 0, 0, 0);
+		INSERT INTO ZGENERICALBUM VALUES (10, 2, 'Trips');
+		INSERT INTO ZGENERICALBUM VALUES (11, 2, 'Family');
+		INSERT INTO ZGENERICALBUM VALUES (12, 3999, NULL);
+		INSERT INTO Z_28ASSETS VALUES (10, 1, 1);
+		INSERT INTO Z_28ASSETS VALUES (11, 1, 2);
+		INSERT INTO Z_28ASSETS VALUES (11, 2, 1);
+		INSERT INTO Z_28ASSETS VALUES (12, 3, 1);
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	photosDB := &Database{
+		db:     db,
+		logger: logger.New(logger.Config{Level: logger.LevelDebug, Output: io.Discard}),
+	}
+
+	schema, err := photosDB.detectSchema()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "Z_28ASSETS", schema.AlbumJoinTable)
+	assert.Equal(t, "Z_28ALBUMS", schema.AlbumJoinAlbumColumn)
+	assert.Equal(t, "Z_3ASSETS", schema.AlbumJoinAssetColumn)
+
+	assets, err := photosDB.GetAssets("/fake/dcim/path")
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, assets, 3) {
+		return
+	}
+
+	albumsByFile := make(map[string][]string)
+	for _, asset := range assets {
+		albumsByFile[asset.Filename] = asset.Albums
+	}
+	assert.Equal(t, []string{"Family", "Trips"}, albumsByFile["IMG_001.HEIC"])
+	assert.Equal(t, []string{"Family"}, albumsByFile["IMG_002.HEIC"])
+	assert.Empty(t, albumsByFile["IMG_003.HEIC"], "untitled albums should be ignored")
+}
+
+func TestDetectSchema_NoAlbumTables(t *testing.T) {
+	tmpDir := t.TempDir()
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "Photos.sqlite"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE ZASSET (Z_PK INTEGER PRIMARY KEY, ZFILENAME TEXT, ZCREATIONDATE REAL)`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	photosDB := &Database{
+		db:     db,
+		logger: logger.New(logger.Config{Level: logger.LevelDebug, Output: io.Discard}),
+	}
+
+	schema, err := photosDB.detectSchema()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, schema.AlbumJoinTable)
+
+	memberships, err := photosDB.getAlbumMemberships(schema)
+	assert.NoError(t, err)
+	assert.Empty(t, memberships)
+}
+
+func TestGetAssets_AdditionalAttributes(t *testing.T) {
+	tmpDir := t.TempDir()
+	db, err := sql.Open("sqlite", filepath.Join(tmpDir, "Photos.sqlite"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	// 2024-03-19 03:00:00 UTC, captured in Denver (UTC-6) on the evening of March 18th
+	_, err = db.Exec(`
+		CREATE TABLE ZASSET (
+			Z_PK INTEGER PRIMARY KEY,
+			ZFILENAME TEXT,
+			ZDIRECTORY TEXT,
+			ZDATECREATED REAL,
+			ZHIDDEN INTEGER,
+			ZTRASHED INTEGER,
+			ZKINDSUBTYPE INTEGER,
+			ZLATITUDE REAL,
+			ZLONGITUDE REAL
+		);
+		CREATE TABLE ZADDITIONALASSETATTRIBUTES (
+			Z_PK INTEGER PRIMARY KEY,
+			ZASSET INTEGER,
+			ZTIMEZONEOFFSET INTEGER,
+			ZTIMEZONENAME TEXT,
+			ZORIGINALFILENAME TEXT,
+			ZREVERSELOCATIONDATA BLOB
+		);
+		INSERT INTO ZASSET VALUES (1, 'IMG_001.HEIC', '100APPLE', 732510000, 0, 0, 0, 40.5853, -105.0844);
+		INSERT INTO ZASSET VALUES (2, 'IMG_002.HEIC', '100APPLE', 732510000, 0, 0, 0, -180, -180);
+		INSERT INTO ZADDITIONALASSETATTRIBUTES VALUES (1, 1, -21600, 'GMT-0600', 'DSC01234.HEIC', NULL);
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// PLRevGeoLocationInfo archive with the postal address Photos geocoded the asset to
+	reverseLocation := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>$archiver</key><string>NSKeyedArchiver</string>
+	<key>$top</key>
+	<dict><key>root</key><dict><key>CF$UID</key><integer>1</integer></dict></dict>
+	<key>$objects</key>
+	<array>
+		<string>$null</string>
+		<dict>
+			<key>countryCode</key><dict><key>CF$UID</key><integer>5</integer></dict>
+			<key>postalAddress</key><dict><key>CF$UID</key><integer>2</integer></dict>
+		</dict>
+		<dict>
+			<key>_city</key><dict><key>CF$UID</key><integer>3</integer></dict>
+			<key>_country</key><dict><key>CF$UID</key><integer>4</integer></dict>
+			<key>_ISOCountryCode</key><dict><key>CF$UID</key><integer>5</integer></dict>
+		</dict>
+		<string>Fort Collins</string>
+		<string>United States</string>
+		<string>US</string>
+	</array>
+</dict>
+</plist>`
+	_, err = db.Exec("UPDATE ZADDITIONALASSETATTRIBUTES SET ZREVERSELOCATIONDATA = ? WHERE ZASSET = 1", []byte(reverseLocation))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	photosDB := &Database{
+		db:     db,
+		logger: logger.New(logger.Config{Level: logger.LevelDebug, Output: io.Discard}),
+	}
+
+	assets, err := photosDB.GetAssets("/fake/dcim/path")
+	if !assert.NoError(t, err) || !assert.Len(t, assets, 2) {
+		return
+	}
+
+	byFile := make(map[string]*types.Asset)
+	for _, asset := range assets {
+		byFile[asset.Filename] = asset
+	}
+
+	withZone := byFile["IMG_001.HEIC"]
+	assert.Equal(t, "GMT-0600", withZone.TimezoneName)
+	assert.Equal(t, "DSC01234.HEIC", withZone.OriginalFilename)
+	if assert.NotNil(t, withZone.TimezoneOffset) {
+		assert.Equal(t, -21600, *withZone.TimezoneOffset)
+	}
+	assert.Equal(t, "2024/03/18/photos/IMG_001.HEIC", withZone.GenerateTargetPath(types.GranularityDay, ""))
+	assert.Equal(t, "Fort Collins", withZone.City)
+	assert.Equal(t, "United States", withZone.Country)
+
+	withoutZone := byFile["IMG_002.HEIC"]
+	assert.Nil(t, withoutZone.TimezoneOffset)
+	assert.Empty(t, withoutZone.OriginalFilename)
+	assert.Empty(t, withoutZone.City)
+	assert.Equal(t, "2024/03/19/photos/IMG_002.HEIC", withoutZone.GenerateTargetPath(types.GranularityDay, ""))
+	assert.True(t, withZone.CreationDate.Equal(withoutZone.CreationDate), "the instant must not change")
+}
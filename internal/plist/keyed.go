@@ -0,0 +1,99 @@
+package plist
+
+import "fmt"
+
+// KeyedArchive is a decoded NSKeyedArchiver archive. Archived objects reference each other
+// through UIDs into a shared object table; Resolve follows those references.
+type KeyedArchive struct {
+	objects []any
+	root    map[string]any
+}
+
+// DecodeKeyedArchive parses an NSKeyedArchiver archive (usually a binary plist)
+func DecodeKeyedArchive(data []byte) (*KeyedArchive, error) {
+	decoded, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	top, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("keyed archive is %T, not a dict", decoded)
+	}
+	objects, ok := top["$objects"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("keyed archive has no $objects table")
+	}
+	entry, ok := top["$top"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("keyed archive has no $top dict")
+	}
+
+	archive := &KeyedArchive{objects: objects}
+	root, ok := archive.Resolve(entry["root"]).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("keyed archive root is not an object")
+	}
+	archive.root = root
+	return archive, nil
+}
+
+// Root returns the archived root object
+func (a *KeyedArchive) Root() map[string]any {
+	return a.root
+}
+
+// Resolve follows a UID reference into the object table; other values are returned unchanged.
+// The "$null" placeholder resolves to nil.
+func (a *KeyedArchive) Resolve(value any) any {
+	uid, ok := value.(UID)
+	if !ok {
+		return value
+	}
+	if uint64(uid) >= uint64(len(a.objects)) {
+		return nil
+	}
+	resolved := a.objects[uid]
+	if s, ok := resolved.(string); ok && s == "$null" {
+		return nil
+	}
+	return resolved
+}
+
+// Int returns an integer field of an archived object
+func (a *KeyedArchive) Int(object map[string]any, key string) (int64, bool) {
+	switch v := a.Resolve(object[key]).(type) {
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// String returns a string field of an archived object, unwrapping NSMutableString objects
+func (a *KeyedArchive) String(object map[string]any, key string) (string, bool) {
+	switch v := a.Resolve(object[key]).(type) {
+	case string:
+		return v, true
+	case map[string]any:
+		s, ok := a.Resolve(v["NS.string"]).(string)
+		return s, ok
+	default:
+		return "", false
+	}
+}
+
+// Data returns a data field of an archived object, unwrapping NSData/NSMutableData objects
+func (a *KeyedArchive) Data(object map[string]any, key string) ([]byte, bool) {
+	switch v := a.Resolve(object[key]).(type) {
+	case []byte:
+		return v, true
+	case map[string]any:
+		data, ok := a.Resolve(v["NS.data"]).([]byte)
+		return data, ok
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,467 @@
+package plist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// UID is a reference to another object, used by NSKeyedArchiver archives
+type UID uint64
+
+// appleEpoch is the reference date for binary plist dates (2001-01-01 UTC)
+var appleEpoch = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// binaryMagic prefixes every binary property list
+const binaryMagic = "bplist00"
+
+// maxDepth bounds nesting so malformed or cyclic plists can't recurse forever
+const maxDepth = 512
+
+// maxDecodedObjects bounds the work done for binary plists whose objects are referenced many times
+const maxDecodedObjects = 1 << 20
+
+// Decode parses an XML or binary ("bplist00") property list.
+// Values are returned as map[string]any, []any, string, int64, uint64 (only for integers
+// above math.MaxInt64), float64, bool, []byte, time.Time or UID.
+func Decode(data []byte) (any, error) {
+	if bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return decodeBinary(data)
+	}
+	return decodeXML(data)
+}
+
+// IsBinary reports whether data is a binary property list
+func IsBinary(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(binaryMagic))
+}
+
+// decodeXML parses an XML property list
+func decodeXML(data []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plist: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "plist" {
+			continue
+		}
+		return decodeXMLValue(decoder, start, 0)
+	}
+}
+
+// decodeXMLValue decodes the value that begins with start
+func decodeXMLValue(decoder *xml.Decoder, start xml.StartElement, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("plist nesting exceeds %d levels", maxDepth)
+	}
+
+	switch start.Name.Local {
+	case "dict":
+		dict := make(map[string]any)
+		var key *string
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read dict: %w", err)
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					k, err := xmlText(decoder)
+					if err != nil {
+						return nil, err
+					}
+					key = &k
+					continue
+				}
+				if key == nil {
+					return nil, fmt.Errorf("dict value <%s> without a key", t.Name.Local)
+				}
+				value, err := decodeXMLValue(decoder, t, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				dict[*key] = value
+				key = nil
+			case xml.EndElement:
+				// XML plists encode UIDs as <dict><key>CF$UID</key><integer>n</integer></dict>
+				if len(dict) == 1 {
+					if uid, ok := dict["CF$UID"].(int64); ok && uid >= 0 {
+						return UID(uid), nil
+					}
+				}
+				return dict, nil
+			}
+		}
+	case "array":
+		array := []any{}
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read array: %w", err)
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				value, err := decodeXMLValue(decoder, t, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, value)
+			case xml.EndElement:
+				return array, nil
+			}
+		}
+	case "string":
+		return xmlText(decoder)
+	case "integer":
+		text, err := xmlText(decoder)
+		if err != nil {
+			return nil, err
+		}
+		return parseXMLInteger(strings.TrimSpace(text))
+	case "real":
+		text, err := xmlText(decoder)
+		if err != nil {
+			return nil, err
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid real %q: %w", text, err)
+		}
+		return value, nil
+	case "true", "false":
+		if err := decoder.Skip(); err != nil {
+			return nil, fmt.Errorf("failed to read boolean: %w", err)
+		}
+		return start.Name.Local == "true", nil
+	case "data":
+		text, err := xmlText(decoder)
+		if err != nil {
+			return nil, err
+		}
+		cleaned := strings.Map(func(r rune) rune {
+			if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+				return -1
+			}
+			return r
+		}, text)
+		value, err := base64.StdEncoding.DecodeString(cleaned)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data: %w", err)
+		}
+		return value, nil
+	case "date":
+		text, err := xmlText(decoder)
+		if err != nil {
+			return nil, err
+		}
+		value, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", text, err)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unsupported plist element <%s>", start.Name.Local)
+	}
+}
+
+// parseXMLInteger parses a decimal or 0x-prefixed hexadecimal integer
+func parseXMLInteger(text string) (any, error) {
+	if value, err := strconv.ParseInt(text, 0, 64); err == nil {
+		return value, nil
+	}
+	value, err := strconv.ParseUint(text, 0, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer %q: %w", text, err)
+	}
+	return value, nil
+}
+
+// xmlText reads the character data of the current element up to its end tag
+func xmlText(decoder *xml.Decoder) (string, error) {
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("failed to read element text: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			return text.String(), nil
+		case xml.StartElement:
+			return "", fmt.Errorf("unexpected element <%s> in text", t.Name.Local)
+		}
+	}
+}
+
+// binaryPlist holds the state needed to decode a binary property list
+type binaryPlist struct {
+	data          []byte
+	offsets       []uint64
+	objectRefSize int
+	decoded       int // objects decoded so far, bounded so shared references can't blow up
+}
+
+// decodeBinary parses a binary property list
+func decodeBinary(data []byte) (any, error) {
+	if len(data) < len(binaryMagic)+32 {
+		return nil, fmt.Errorf("binary plist too short")
+	}
+
+	trailer := data[len(data)-32:]
+	offsetSize := int(trailer[6])
+	objectRefSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:16])
+	topObject := binary.BigEndian.Uint64(trailer[16:24])
+	offsetTableOffset := binary.BigEndian.Uint64(trailer[24:32])
+
+	if offsetSize < 1 || offsetSize > 8 || objectRefSize < 1 || objectRefSize > 8 {
+		return nil, fmt.Errorf("invalid binary plist trailer")
+	}
+	if numObjects == 0 || topObject >= numObjects {
+		return nil, fmt.Errorf("invalid binary plist object count")
+	}
+	tableEnd := offsetTableOffset + numObjects*uint64(offsetSize)
+	if numObjects > uint64(len(data)) || offsetTableOffset > uint64(len(data)) || tableEnd > uint64(len(data)-32) {
+		return nil, fmt.Errorf("binary plist offset table out of range")
+	}
+
+	p := &binaryPlist{
+		data:          data,
+		offsets:       make([]uint64, numObjects),
+		objectRefSize: objectRefSize,
+	}
+	for i := range p.offsets {
+		start := offsetTableOffset + uint64(i*offsetSize)
+		p.offsets[i] = readUint(data[start : start+uint64(offsetSize)])
+	}
+
+	return p.object(topObject, 0)
+}
+
+// object decodes the object with the given index
+func (p *binaryPlist) object(index uint64, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("plist nesting exceeds %d levels", maxDepth)
+	}
+	if index >= uint64(len(p.offsets)) {
+		return nil, fmt.Errorf("object reference %d out of range", index)
+	}
+	p.decoded++
+	if p.decoded > maxDecodedObjects {
+		return nil, fmt.Errorf("binary plist references more than %d objects", maxDecodedObjects)
+	}
+	offset := p.offsets[index]
+	if offset >= uint64(len(p.data)) {
+		return nil, fmt.Errorf("object offset %d out of range", offset)
+	}
+
+	marker := p.data[offset]
+	kind, info := marker>>4, marker&0x0F
+	pos := offset + 1
+
+	switch kind {
+	case 0x0:
+		switch marker {
+		case 0x08:
+			return false, nil
+		case 0x09:
+			return true, nil
+		default:
+			return nil, nil
+		}
+	case 0x1:
+		size := uint64(1) << info
+		raw, err := p.bytes(pos, size)
+		if err != nil {
+			return nil, err
+		}
+		switch size {
+		case 1, 2, 4:
+			return int64(readUint(raw)), nil
+		case 8:
+			return int64(binary.BigEndian.Uint64(raw)), nil
+		case 16:
+			// 128-bit integers only appear for values that don't fit in int64; keep the low 64 bits
+			return binary.BigEndian.Uint64(raw[8:]), nil
+		default:
+			return nil, fmt.Errorf("unsupported integer size %d", size)
+		}
+	case 0x2:
+		size := uint64(1) << info
+		raw, err := p.bytes(pos, size)
+		if err != nil {
+			return nil, err
+		}
+		switch size {
+		case 4:
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(raw))), nil
+		case 8:
+			return math.Float64frombits(binary.BigEndian.Uint64(raw)), nil
+		default:
+			return nil, fmt.Errorf("unsupported real size %d", size)
+		}
+	case 0x3:
+		raw, err := p.bytes(pos, 8)
+		if err != nil {
+			return nil, err
+		}
+		seconds := math.Float64frombits(binary.BigEndian.Uint64(raw))
+		whole, frac := math.Modf(seconds)
+		return appleEpoch.Add(time.Duration(whole) * time.Second).Add(time.Duration(frac * float64(time.Second))), nil
+	case 0x4:
+		length, start, err := p.length(info, pos, depth)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := p.bytes(start, length)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), raw...), nil
+	case 0x5:
+		length, start, err := p.length(info, pos, depth)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := p.bytes(start, length)
+		if err != nil {
+			return nil, err
+		}
+		return string(raw), nil
+	case 0x6:
+		length, start, err := p.length(info, pos, depth)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := p.bytes(start, length*2)
+		if err != nil {
+			return nil, err
+		}
+		units := make([]uint16, length)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(raw[i*2:])
+		}
+		return string(utf16.Decode(units)), nil
+	case 0x8:
+		raw, err := p.bytes(pos, uint64(info)+1)
+		if err != nil {
+			return nil, err
+		}
+		return UID(readUint(raw)), nil
+	case 0xA, 0xC:
+		length, start, err := p.length(info, pos, depth)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := p.refs(start, length)
+		if err != nil {
+			return nil, err
+		}
+		array := make([]any, 0, length)
+		for _, ref := range refs {
+			value, err := p.object(ref, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		return array, nil
+	case 0xD:
+		length, start, err := p.length(info, pos, depth)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := p.refs(start, length*2)
+		if err != nil {
+			return nil, err
+		}
+		dict := make(map[string]any, length)
+		for i := uint64(0); i < length; i++ {
+			key, err := p.object(refs[i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("dict key is %T, not a string", key)
+			}
+			value, err := p.object(refs[length+i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			dict[keyString] = value
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("unsupported binary plist object type 0x%x", kind)
+	}
+}
+
+// length returns the element count of a data, string, array or dict object and where its contents start
+func (p *binaryPlist) length(info byte, pos uint64, depth int) (uint64, uint64, error) {
+	if info != 0x0F {
+		return uint64(info), pos, nil
+	}
+	if pos >= uint64(len(p.data)) || p.data[pos]>>4 != 0x1 {
+		return 0, 0, fmt.Errorf("invalid length marker at offset %d", pos)
+	}
+	size := uint64(1) << (p.data[pos] & 0x0F)
+	raw, err := p.bytes(pos+1, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	length := readUint(raw)
+	if length > uint64(len(p.data)) {
+		return 0, 0, fmt.Errorf("object length %d out of range", length)
+	}
+	return length, pos + 1 + size, nil
+}
+
+// refs reads count object references starting at pos
+func (p *binaryPlist) refs(pos, count uint64) ([]uint64, error) {
+	raw, err := p.bytes(pos, count*uint64(p.objectRefSize))
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]uint64, count)
+	for i := range refs {
+		start := i * p.objectRefSize
+		refs[i] = readUint(raw[start : start+p.objectRefSize])
+	}
+	return refs, nil
+}
+
+// bytes returns length bytes starting at pos, checking bounds
+func (p *binaryPlist) bytes(pos, length uint64) ([]byte, error) {
+	if length > uint64(len(p.data)) || pos > uint64(len(p.data))-length {
+		return nil, fmt.Errorf("object data out of range at offset %d", pos)
+	}
+	return p.data[pos : pos+length], nil
+}
+
+// readUint reads a big-endian unsigned integer of up to 8 bytes
+func readUint(raw []byte) uint64 {
+	var value uint64
+	for _, b := range raw {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
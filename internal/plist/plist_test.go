@@ -0,0 +1,173 @@
+package plist
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildBinaryPlist assembles a bplist00 file from encoded objects; object 0 is the top object
+func buildBinaryPlist(objects [][]byte) []byte {
+	data := []byte(binaryMagic)
+	offsets := make([]uint64, len(objects))
+	for i, object := range objects {
+		offsets[i] = uint64(len(data))
+		data = append(data, object...)
+	}
+
+	offsetTable := uint64(len(data))
+	for _, offset := range offsets {
+		data = append(data, byte(offset))
+	}
+
+	trailer := make([]byte, 32)
+	trailer[6] = 1 // offset size
+	trailer[7] = 1 // object ref size
+	binary.BigEndian.PutUint64(trailer[8:], uint64(len(objects)))
+	binary.BigEndian.PutUint64(trailer[16:], 0)
+	binary.BigEndian.PutUint64(trailer[24:], offsetTable)
+	return append(data, trailer...)
+}
+
+func TestDecodeXML(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Device Name</key>
+	<string>Grant&apos;s iPhone</string>
+	<key>IsEncrypted</key>
+	<true/>
+	<key>Version</key>
+	<integer>10</integer>
+	<key>Ratio</key>
+	<real>1.5</real>
+	<key>Key</key>
+	<data>
+	AQID
+	BA==
+	</data>
+	<key>Date</key>
+	<date>2025-09-18T12:00:00Z</date>
+	<key>Apps</key>
+	<array>
+		<string>com.example.one</string>
+		<string>com.example.two</string>
+	</array>
+	<key>Ref</key>
+	<dict>
+		<key>CF$UID</key>
+		<integer>7</integer>
+	</dict>
+</dict>
+</plist>`)
+
+	decoded, err := Decode(data)
+	require.NoError(t, err)
+	dict, ok := decoded.(map[string]any)
+	require.True(t, ok)
+
+	assert.Equal(t, "Grant's iPhone", dict["Device Name"])
+	assert.Equal(t, true, dict["IsEncrypted"])
+	assert.Equal(t, int64(10), dict["Version"])
+	assert.Equal(t, 1.5, dict["Ratio"])
+	assert.Equal(t, []byte{1, 2, 3, 4}, dict["Key"])
+	assert.Equal(t, time.Date(2025, 9, 18, 12, 0, 0, 0, time.UTC), dict["Date"])
+	assert.Equal(t, []any{"com.example.one", "com.example.two"}, dict["Apps"])
+	assert.Equal(t, UID(7), dict["Ref"])
+	assert.False(t, IsBinary(data))
+}
+
+func TestDecodeBinary(t *testing.T) {
+	data := buildBinaryPlist([][]byte{
+		{0xD4, 1, 2, 3, 4, 5, 6, 7, 8}, // dict with 4 entries
+		{0x51, 'n'},                    // "n"
+		{0x51, 'b'},                    // "b"
+		{0x51, 'd'},                    // "d"
+		{0x51, 'u'},                    // "u"
+		{0x11, 0x01, 0x2C},             // 300
+		{0x09},                         // true
+		{0x43, 0xDE, 0xAD, 0xBE},       // 3 bytes of data
+		{0x80, 0x05},                   // UID 5
+	})
+
+	require.True(t, IsBinary(data))
+	decoded, err := Decode(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"n": int64(300),
+		"b": true,
+		"d": []byte{0xDE, 0xAD, 0xBE},
+		"u": UID(5),
+	}, decoded)
+}
+
+func TestDecodeBinaryMalformed(t *testing.T) {
+	selfReference := buildBinaryPlist([][]byte{{0xA1, 0}}) // array containing itself
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "too_short", data: []byte(binaryMagic + "\x00")},
+		{name: "self_reference", data: selfReference},
+		{name: "ref_out_of_range", data: buildBinaryPlist([][]byte{{0xA1, 9}})},
+		{name: "length_out_of_range", data: buildBinaryPlist([][]byte{{0x4F, 0x10, 0x7F}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeKeyedArchive(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>$archiver</key><string>NSKeyedArchiver</string>
+	<key>$top</key>
+	<dict><key>root</key><dict><key>CF$UID</key><integer>1</integer></dict></dict>
+	<key>$objects</key>
+	<array>
+		<string>$null</string>
+		<dict>
+			<key>Size</key><integer>1234</integer>
+			<key>RelativePath</key><dict><key>CF$UID</key><integer>2</integer></dict>
+			<key>EncryptionKey</key><dict><key>CF$UID</key><integer>3</integer></dict>
+			<key>Target</key><dict><key>CF$UID</key><integer>0</integer></dict>
+		</dict>
+		<string>Media/DCIM/100APPLE/IMG_0001.HEIC</string>
+		<dict>
+			<key>NS.data</key><data>AwAAAA==</data>
+		</dict>
+	</array>
+</dict>
+</plist>`)
+
+	archive, err := DecodeKeyedArchive(data)
+	require.NoError(t, err)
+	root := archive.Root()
+
+	size, ok := archive.Int(root, "Size")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234), size)
+
+	path, ok := archive.String(root, "RelativePath")
+	assert.True(t, ok)
+	assert.Equal(t, "Media/DCIM/100APPLE/IMG_0001.HEIC", path)
+
+	key, ok := archive.Data(root, "EncryptionKey")
+	assert.True(t, ok)
+	assert.Equal(t, []byte{3, 0, 0, 0}, key)
+
+	assert.Nil(t, archive.Resolve(root["Target"]))
+	_, ok = archive.String(root, "Missing")
+	assert.False(t, ok)
+}
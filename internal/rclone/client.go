@@ -6,8 +6,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -263,7 +265,7 @@ func CreateClient(remote string, parallel int, verify, dryRun, skipExisting bool
 		verify:        verify,
 		dryRun:        dryRun,
 		skipExisting:  skipExisting,
-		remotePreScan: false, // Disabled for now
+		remotePreScan: false, // Enabled with SetRemotePreScan
 		logger:        logger,
 		logLevel:      logLevel,
 		batchTimeout:  30 * time.Minute, // Default 30 minute timeout per batch
@@ -280,6 +282,11 @@ func (c *Client) SetBatchTimeout(timeout time.Duration) {
 	c.batchTimeout = timeout
 }
 
+// SetRemotePreScan enables listing the remote while planning so existing files are marked as skips up front
+func (c *Client) SetRemotePreScan(enabled bool) {
+	c.remotePreScan = enabled
+}
+
 // getBaseRemote extracts the base remote name from the remote specification
 func (c *Client) getBaseRemote() string {
 	if strings.Contains(c.remote, ":") && !strings.HasSuffix(c.remote, ":") {
@@ -518,14 +525,16 @@ func (c *Client) UploadBatch(ctx context.Context, entries []manifest.Entry, upda
 
 // uploadChunk handles uploading a chunk of entries efficiently using rclone batch operations
 func (c *Client) uploadChunk(ctx context.Context, chunk []manifest.Entry, allEntries []manifest.Entry, baseIndex int, updateCallback func(int, manifest.OperationStatus, string), progressCallback ProgressCallback) error {
-	// Group entries by target directory to optimize rclone calls
+	// Group entries by target directory to optimize rclone calls, remembering each entry's index in allEntries
 	dirGroups := make(map[string][]manifest.Entry)
-	for _, entry := range chunk {
+	dirIndexes := make(map[string][]int)
+	for i, entry := range chunk {
 		dir := filepath.Dir(entry.TargetPath)
 		if dir == "." {
 			dir = ""
 		}
 		dirGroups[dir] = append(dirGroups[dir], entry)
+		dirIndexes[dir] = append(dirIndexes[dir], baseIndex+i)
 	}
 	c.logDebug("upload chunk grouping complete", "groups", len(dirGroups), "base_index", baseIndex)
 
@@ -618,9 +627,13 @@ func (c *Client) uploadChunk(ctx context.Context, chunk []manifest.Entry, allEnt
 		// Add progress reporting
 		args = append(args, "--progress", "--stats-one-line")
 
-		// Add verbose output in debug mode
+		// Log per-file results as JSON so each entry gets its own status.
+		// INFO reports copies and errors; DEBUG additionally reports files skipped because they exist.
+		args = append(args, "--use-json-log")
 		if c.logLevel == "debug" {
-			args = append(args, "--verbose")
+			args = append(args, "--log-level=DEBUG")
+		} else {
+			args = append(args, "--log-level=INFO")
 		}
 
 		c.logDebug("executing rclone batch", "dir", targetDir, "file_count", len(groupEntries), "args", strings.Join(args, " "))
@@ -689,10 +702,13 @@ func (c *Client) uploadChunk(ctx context.Context, chunk []manifest.Entry, allEnt
 			}
 		}
 
-		if err := cmd.Wait(); err != nil {
-			// Log stderr output for debugging
-			if stderrOutput := stderrBuf.String(); stderrOutput != "" {
-				c.logError("rclone stderr output", "stderr", stderrOutput)
+		waitErr := cmd.Wait()
+		results, otherOutput := parseJSONLog(&stderrBuf)
+
+		if waitErr != nil {
+			// Log stderr output that isn't a per-file result for debugging
+			if len(otherOutput) > 0 {
+				c.logError("rclone stderr output", "stderr", strings.Join(otherOutput, "\n"))
 			}
 
 			// Check if error was due to context cancellation or timeout
@@ -705,45 +721,22 @@ func (c *Client) uploadChunk(ctx context.Context, chunk []manifest.Entry, allEnt
 				return batchCtx.Err()
 			}
 
-			c.logError("rclone batch failed", "error", err, "dir", targetDir, "files", len(groupEntries), "exit_code", cmd.ProcessState.ExitCode())
-			// Mark all entries in this batch as failed
-			for _, entry := range groupEntries {
-				// Find original index
-				originalIndex := -1
-				for j, originalEntry := range allEntries {
-					if originalEntry.SourcePath == entry.SourcePath {
-						originalIndex = j
-						break
-					}
-				}
-				if originalIndex >= 0 {
-					updateCallback(originalIndex, manifest.StatusFailed, fmt.Sprintf("batch upload failed: %v", err))
-				}
-			}
-			return fmt.Errorf("rclone batch upload failed: %w", err)
-		}
-
-		// Always log stderr output even on success to catch warnings
-		if stderrOutput := stderrBuf.String(); stderrOutput != "" {
-			c.logDebug("rclone stderr output (success case)", "stderr", stderrOutput)
+			c.logError("rclone batch failed", "error", waitErr, "dir", targetDir, "files", len(groupEntries), "exit_code", cmd.ProcessState.ExitCode())
+		} else if len(otherOutput) > 0 {
+			// Always log stderr output even on success to catch warnings
+			c.logDebug("rclone stderr output (success case)", "stderr", strings.Join(otherOutput, "\n"))
 		}
 
-		c.logDebug("rclone batch complete", "dir", targetDir, "files", len(groupEntries), "exit_code", cmd.ProcessState.ExitCode())
+		c.logDebug("rclone batch complete", "dir", targetDir, "files", len(groupEntries), "results", len(results), "exit_code", cmd.ProcessState.ExitCode())
 
-		// Mark all entries in this batch as successful
-		for _, entry := range groupEntries {
+		// Record the outcome rclone reported for each file; one failed file no longer fails the whole group
+		for i, entry := range groupEntries {
 			completed++
-			// Find original index
-			originalIndex := -1
-			for j, originalEntry := range allEntries {
-				if originalEntry.SourcePath == entry.SourcePath {
-					originalIndex = j
-					break
-				}
-			}
-			if originalIndex >= 0 {
-				updateCallback(originalIndex, manifest.StatusUploaded, "")
+			result := resolveObjectResult(results, entry.TargetPath, waitErr)
+			if result.status == manifest.StatusFailed {
+				c.logError("file upload failed", "source", entry.SourcePath, "target", entry.TargetPath, "error", result.message)
 			}
+			updateCallback(dirIndexes[targetDir][i], result.status, result.message)
 
 			if progressCallback != nil {
 				progressCallback(completed, total, filepath.Base(entry.SourcePath))
@@ -754,6 +747,75 @@ func (c *Client) uploadChunk(ctx context.Context, chunk []manifest.Entry, allEnt
 	return nil
 }
 
+// objectResult is the outcome rclone reported for a single file
+type objectResult struct {
+	status  manifest.OperationStatus
+	message string
+}
+
+// jsonLogLine is one line of rclone's --use-json-log output
+type jsonLogLine struct {
+	Level  string `json:"level"`
+	Msg    string `json:"msg"`
+	Object string `json:"object"`
+}
+
+// parseJSONLog reads rclone's --use-json-log output and returns the result for each object
+// (keyed by its path relative to the copy source) plus any lines that are not per-object events
+func parseJSONLog(r io.Reader) (map[string]objectResult, []string) {
+	results := make(map[string]objectResult)
+	var other []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var event jsonLogLine
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Object == "" {
+			other = append(other, line)
+			continue
+		}
+
+		key := normalizeObjectPath(event.Object)
+		switch {
+		case event.Level == "error" || event.Level == "critical":
+			results[key] = objectResult{status: manifest.StatusFailed, message: event.Msg}
+		case strings.Contains(event.Msg, "Copied ("):
+			// Covers "Copied (new)", "Copied (replaced existing)" and multi-thread variants; a copy after a retried error wins
+			results[key] = objectResult{status: manifest.StatusUploaded}
+		case strings.Contains(event.Msg, "Destination exists, skipping") || strings.Contains(event.Msg, "Unchanged skipping"):
+			if _, seen := results[key]; !seen {
+				results[key] = objectResult{status: manifest.StatusSkipped}
+			}
+		}
+	}
+
+	return results, other
+}
+
+// resolveObjectResult determines the status of an entry from rclone's per-object results.
+// A file rclone said nothing about was left alone: skipped when the copy succeeded
+// (it already existed or was unchanged), failed when the copy exited with an error.
+func resolveObjectResult(results map[string]objectResult, targetPath string, copyErr error) objectResult {
+	if result, ok := results[normalizeObjectPath(targetPath)]; ok {
+		return result
+	}
+	if copyErr != nil {
+		return objectResult{status: manifest.StatusFailed, message: fmt.Sprintf("batch upload failed: %v", copyErr)}
+	}
+	return objectResult{status: manifest.StatusSkipped}
+}
+
+// normalizeObjectPath converts a target path to the form rclone logs objects in
+func normalizeObjectPath(p string) string {
+	p = strings.ReplaceAll(p, "\\", "/")
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
 // CheckRemoteExists checks if a file exists on the remote
 func (c *Client) CheckRemoteExists(ctx context.Context, remotePath string) (bool, error) {
 	fullPath := fmt.Sprintf("%s:%s", c.remote, remotePath)
@@ -805,8 +867,8 @@ func (c *Client) BatchCheckRemoteExists(ctx context.Context, entries []manifest.
 			c.logDebug("context cancelled before directory listing", "dir", dir)
 			return existingFiles, ctx.Err()
 		}
-		remotePath := fmt.Sprintf("%s:%s", c.remote, dir)
-		args := []string{"lsf", remotePath, "-R"}
+		remotePath := c.buildRemotePath(dir)
+		args := []string{"lsf", remotePath, "-R", "--files-only"}
 
 		// Add Google Drive optimizations for directory listing
 		if c.isGoogleDriveRemote() {
@@ -827,8 +889,7 @@ func (c *Client) BatchCheckRemoteExists(ctx context.Context, entries []manifest.
 		for _, file := range files {
 			if file != "" {
 				// Construct full path relative to target
-				fullPath := filepath.Join(dir, file)
-				existingFiles[fullPath] = true
+				existingFiles[normalizeObjectPath(dir+"/"+file)] = true
 			}
 		}
 		c.logDebug("directory listing processed", "dir", dir, "files", len(files))
@@ -840,8 +901,8 @@ func (c *Client) BatchCheckRemoteExists(ctx context.Context, entries []manifest.
 
 // listAllRemoteFiles lists all files on the remote recursively
 func (c *Client) listAllRemoteFiles(ctx context.Context) (map[string]bool, error) {
-	remotePath := fmt.Sprintf("%s:", c.remote)
-	args := []string{"lsf", remotePath, "-R"}
+	remotePath := c.buildRemotePath("")
+	args := []string{"lsf", remotePath, "-R", "--files-only"}
 
 	// Add Google Drive optimizations for recursive listing
 	if c.isGoogleDriveRemote() {
@@ -865,7 +926,7 @@ func (c *Client) listAllRemoteFiles(ctx context.Context) (map[string]bool, error
 			return existingFiles, ctx.Err()
 		}
 		if file != "" {
-			existingFiles[file] = true
+			existingFiles[normalizeObjectPath(file)] = true
 		}
 	}
 	c.logDebug("listed all remote files", "count", len(existingFiles))
@@ -997,8 +1058,8 @@ func (c *Client) CreateUploadPlan(ctx context.Context, entries []manifest.Entry)
 		// Define remote path for metadata file
 		remoteMetadataPath := c.buildRemotePath("metadata/extraction-metadata-" + timestamp + ".json")
 
-		// Since remotePreScan is disabled, we cannot check for existing files here.
-		// We will rely on rclone's --ignore-existing flag during the copy operation.
+		// The metadata file is never part of the pre-scan; rclone's --ignore-existing
+		// handles an existing copy during the copy operation.
 		c.logDebug("uploading metadata file to remote", "local_path", metadataPath, "remote_path", remoteMetadataPath)
 		cmd := exec.CommandContext(ctx, "rclone", "copyto", "--ignore-existing", metadataPath, remoteMetadataPath)
 		setupRcloneCmd(cmd)
@@ -1011,22 +1072,38 @@ func (c *Client) CreateUploadPlan(ctx context.Context, entries []manifest.Entry)
 		}
 	}
 
-	// Create the upload plan
+	// Without a pre-scan every file is planned for upload and rclone's --ignore-existing
+	// handles skipping at runtime. Pre-scanning is pointless when overwriting.
+	var existingFiles map[string]bool
+	if c.remotePreScan && c.skipExisting {
+		existingFiles = c.performRemotePreScan(ctx, entries)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	return buildUploadPlan(entries, existingFiles), nil
+}
+
+// buildUploadPlan plans an upload for every entry, except those whose target path is in existingFiles
+func buildUploadPlan(entries []manifest.Entry, existingFiles map[string]bool) []UploadPlanEntry {
 	var planEntries []UploadPlanEntry
 
-	// Populate the upload plan
 	for _, entry := range entries {
 		planEntry := UploadPlanEntry{
 			Entry:  entry,
 			Action: ActionUpload,
 		}
 
-		// Since remotePreScan is disabled, we assume all files need to be uploaded.
-		// rclone's --ignore-existing will handle skipping at runtime.
+		if existingFiles[normalizeObjectPath(entry.TargetPath)] {
+			planEntry.Action = ActionSkip
+			planEntry.Reason = "already exists on remote"
+		}
+
 		planEntries = append(planEntries, planEntry)
 	}
 
-	return planEntries, nil
+	return planEntries
 }
 
 // UploadAction represents the action to take for an upload
@@ -1042,6 +1119,7 @@ const (
 type UploadPlanEntry struct {
 	Entry  manifest.Entry `json:"entry"`
 	Action UploadAction   `json:"action"`
+	Reason string         `json:"reason,omitempty"` // why the entry is skipped
 	Error  string         `json:"error,omitempty"`
 }
 
@@ -1058,7 +1136,7 @@ func PrintUploadPlan(plan []UploadPlanEntry) {
 	uploadCount := 0
 	skipCount := 0
 	errorCount := 0
-	var totalSize int64
+	var totalSize, skipSize int64
 
 	for _, entry := range plan {
 		switch entry.Action {
@@ -1071,8 +1149,15 @@ func PrintUploadPlan(plan []UploadPlanEntry) {
 				humanizeBytes(entry.Entry.FileSize))
 		case ActionSkip:
 			skipCount++
-			fmt.Printf("SKIP:   %s (already exists)\n",
-				filepath.Base(entry.Entry.SourcePath))
+			skipSize += entry.Entry.FileSize
+			reason := entry.Reason
+			if reason == "" {
+				reason = "already exists"
+			}
+			fmt.Printf("SKIP:   %s -> %s (%s)\n",
+				filepath.Base(entry.Entry.SourcePath),
+				entry.Entry.TargetPath,
+				reason)
 		case ActionError:
 			errorCount++
 			fmt.Printf("ERROR:  %s (%s)\n",
@@ -1083,7 +1168,7 @@ func PrintUploadPlan(plan []UploadPlanEntry) {
 
 	fmt.Printf("\nSummary:\n")
 	fmt.Printf("  Upload: %d files (%s)\n", uploadCount, humanizeBytes(totalSize))
-	fmt.Printf("  Skip:   %d files\n", skipCount)
+	fmt.Printf("  Skip:   %d files (%s)\n", skipCount, humanizeBytes(skipSize))
 	if errorCount > 0 {
 		fmt.Printf("  Error:  %d files\n", errorCount)
 	}
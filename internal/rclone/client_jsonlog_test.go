@@ -0,0 +1,66 @@
+package rclone
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grantbirki/gh-photos/internal/manifest"
+)
+
+func TestParseJSONLog(t *testing.T) {
+	log := strings.Join([]string{
+		`{"level":"info","msg":"Copied (new)","object":"2024/03/18/photos/IMG_0001.HEIC","objectType":"*local.Object","source":"operations/copy.go:368","time":"2024-03-18T10:00:00Z"}`,
+		`{"level":"debug","msg":"Destination exists, skipping","object":"2024/03/18/photos/IMG_0002.HEIC","objectType":"*local.Object","source":"operations/operations.go:1919","time":"2024-03-18T10:00:00Z"}`,
+		`{"level":"error","msg":"Failed to copy: permission denied","object":"2024/03/18/photos/IMG_0003.HEIC","objectType":"*local.Object","source":"operations/copy.go:368","time":"2024-03-18T10:00:00Z"}`,
+		`{"level":"error","msg":"Failed to copy: connection reset","object":"2024/03/18/photos/IMG_0004.HEIC","objectType":"*local.Object","source":"operations/copy.go:368","time":"2024-03-18T10:00:00Z"}`,
+		`{"level":"info","msg":"Copied (replaced existing)","object":"2024/03/18/photos/IMG_0004.HEIC","objectType":"*local.Object","source":"operations/copy.go:368","time":"2024-03-18T10:00:01Z"}`,
+		`{"level":"info","msg":"Transferred: 1 / 2, 50%","stats":{"bytes":1024},"time":"2024-03-18T10:00:01Z"}`,
+		`Transferred:   	    1 KiB / 2 KiB, 50%`,
+	}, "\n")
+
+	results, other := parseJSONLog(strings.NewReader(log))
+
+	expected := map[string]manifest.OperationStatus{
+		"2024/03/18/photos/IMG_0001.HEIC": manifest.StatusUploaded,
+		"2024/03/18/photos/IMG_0002.HEIC": manifest.StatusSkipped,
+		"2024/03/18/photos/IMG_0003.HEIC": manifest.StatusFailed,
+		"2024/03/18/photos/IMG_0004.HEIC": manifest.StatusUploaded, // a retry that succeeded wins
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("parseJSONLog returned %d results, want %d: %+v", len(results), len(expected), results)
+	}
+	for object, status := range expected {
+		if got := results[object].status; got != status {
+			t.Errorf("status for %s = %q, want %q", object, got, status)
+		}
+	}
+	if msg := results["2024/03/18/photos/IMG_0003.HEIC"].message; msg != "Failed to copy: permission denied" {
+		t.Errorf("unexpected error message: %q", msg)
+	}
+	if len(other) != 2 {
+		t.Errorf("expected 2 non-object lines, got %d: %v", len(other), other)
+	}
+}
+
+func TestResolveObjectResult(t *testing.T) {
+	results := map[string]objectResult{
+		"2024/03/18/photos/IMG_0001.HEIC": {status: manifest.StatusUploaded},
+	}
+
+	// Target paths are matched regardless of separators
+	if got := resolveObjectResult(results, "2024\\03\\18\\photos\\IMG_0001.HEIC", nil); got.status != manifest.StatusUploaded {
+		t.Errorf("expected uploaded, got %q", got.status)
+	}
+
+	// Files rclone didn't mention were skipped when the copy succeeded...
+	if got := resolveObjectResult(results, "2024/03/18/photos/IMG_0002.HEIC", nil); got.status != manifest.StatusSkipped {
+		t.Errorf("expected skipped, got %q", got.status)
+	}
+
+	// ...and failed when it didn't
+	got := resolveObjectResult(results, "2024/03/18/photos/IMG_0002.HEIC", errors.New("exit status 1"))
+	if got.status != manifest.StatusFailed || got.message != "batch upload failed: exit status 1" {
+		t.Errorf("expected failed with batch error, got %+v", got)
+	}
+}
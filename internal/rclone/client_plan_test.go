@@ -0,0 +1,34 @@
+package rclone
+
+import (
+	"testing"
+
+	"github.com/grantbirki/gh-photos/internal/manifest"
+)
+
+func TestBuildUploadPlan(t *testing.T) {
+	entries := []manifest.Entry{
+		{SourcePath: "/backup/IMG_0001.HEIC", TargetPath: "2024/03/18/photos/IMG_0001.HEIC", FileSize: 1000},
+		{SourcePath: "/backup/IMG_0002.HEIC", TargetPath: "2024/03/18/photos/IMG_0002.HEIC", FileSize: 2000},
+	}
+
+	// Without a pre-scan everything is uploaded
+	plan := buildUploadPlan(entries, nil)
+	for _, entry := range plan {
+		if entry.Action != ActionUpload {
+			t.Errorf("expected upload for %s without pre-scan, got %s", entry.Entry.TargetPath, entry.Action)
+		}
+	}
+
+	// Files found on the remote are skipped with a reason
+	plan = buildUploadPlan(entries, map[string]bool{"2024/03/18/photos/IMG_0002.HEIC": true})
+	if len(plan) != 2 {
+		t.Fatalf("expected 2 plan entries, got %d", len(plan))
+	}
+	if plan[0].Action != ActionUpload {
+		t.Errorf("expected upload for IMG_0001, got %s", plan[0].Action)
+	}
+	if plan[1].Action != ActionSkip || plan[1].Reason != "already exists on remote" {
+		t.Errorf("expected skip with reason for IMG_0002, got %s (%q)", plan[1].Action, plan[1].Reason)
+	}
+}
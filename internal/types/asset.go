@@ -20,8 +20,104 @@ const (
 	AssetTypeScreenshot AssetType = "screenshots"
 	AssetTypeBurst      AssetType = "burst"
 	AssetTypeLivePhoto  AssetType = "live_photos"
+
+	// Photo subtypes
+	AssetTypePortrait AssetType = "portrait" // photo with a depth effect
+	AssetTypePanorama AssetType = "panorama"
+	AssetTypeRaw      AssetType = "raw" // RAW or ProRAW (DNG) photo
+
+	// Video subtypes
+	AssetTypeSlomo           AssetType = "slomo"
+	AssetTypeTimelapse       AssetType = "timelapse"
+	AssetTypeCinematic       AssetType = "cinematic" // video with a depth effect
+	AssetTypeScreenRecording AssetType = "screen_recording"
+)
+
+// Base returns the type a photo or video subtype refines (AssetTypePortrait -> AssetTypePhoto).
+// Other types are returned unchanged.
+func (t AssetType) Base() AssetType {
+	switch t {
+	case AssetTypePortrait, AssetTypePanorama, AssetTypeRaw:
+		return AssetTypePhoto
+	case AssetTypeSlomo, AssetTypeTimelapse, AssetTypeCinematic, AssetTypeScreenRecording:
+		return AssetTypeVideo
+	}
+	return t
+}
+
+// Subtype returns the media subtype, such as portrait or slomo, or an empty string for types that
+// aren't a subtype
+func (t AssetType) Subtype() string {
+	if t == t.Base() {
+		return ""
+	}
+	return string(t)
+}
+
+// IsVideo reports whether assets of the type are videos
+func (t AssetType) IsVideo() bool {
+	return t.Base() == AssetTypeVideo
+}
+
+// Matches reports whether a --types value selects the type (case-insensitive). "photos" and
+// "videos" also select their subtypes, which were classified as photos and videos before subtypes
+// were detected.
+func (t AssetType) Matches(name string) bool {
+	name = strings.TrimSpace(name)
+	return strings.EqualFold(string(t), name) || strings.EqualFold(string(t.Base()), name)
+}
+
+// LivePhotoMode controls which parts of a Live Photo are uploaded
+type LivePhotoMode string
+
+const (
+	LivePhotoModeBoth  LivePhotoMode = "both"
+	LivePhotoModeStill LivePhotoMode = "still"
+	LivePhotoModeVideo LivePhotoMode = "video"
 )
 
+// Live Photo component roles recorded in manifests and audit trails
+const (
+	LivePhotoRoleStill = "still"
+	LivePhotoRoleVideo = "video"
+)
+
+// BurstMode controls which frames of a burst are uploaded
+type BurstMode string
+
+const (
+	BurstModeAll    BurstMode = "all"    // every frame
+	BurstModePicked BurstMode = "picked" // frames the user selected
+	BurstModeKey    BurstMode = "key"    // the key frame shown for the burst in the library
+)
+
+// BurstPick holds the bits of ZASSET.ZAVALANCHEPICKTYPE describing how a burst frame was chosen
+type BurstPick int
+
+const (
+	BurstPickNotSelected BurstPick = 1 << 1 // frame the user didn't keep
+	BurstPickAuto        BurstPick = 1 << 2 // frame the camera suggested
+	BurstPickUser        BurstPick = 1 << 3 // frame the user selected
+	BurstPickKey         BurstPick = 1 << 4 // key frame
+)
+
+// Has reports whether all bits of flag are set
+func (p BurstPick) Has(flag BurstPick) bool {
+	return p&flag == flag
+}
+
+// EditsMode controls whether originals, edited renders, or both are uploaded for edited assets
+type EditsMode string
+
+const (
+	EditsModeOriginal EditsMode = "original"
+	EditsModeEdited   EditsMode = "edited"
+	EditsModeBoth     EditsMode = "both"
+)
+
+// EditedSuffix is appended to the base name of an edited render on the remote (IMG_0001.HEIC -> IMG_0001_edited.jpg)
+const EditedSuffix = "_edited"
+
 // AssetFlags represents various flags from the Photos database
 type AssetFlags struct {
 	Hidden           bool
@@ -29,25 +125,78 @@ type AssetFlags struct {
 	Screenshot       bool
 	Burst            bool
 	LivePhoto        bool
+	Edited           bool // asset has adjustments (edited on device)
+	Favorite         bool // marked as a favorite in the Photos app
 	BurstID          *string
+	BurstPick        BurstPick // how the burst frame was chosen (0 outside bursts or when unknown)
 	LivePhotoVideoID *string
 }
 
+// Location holds the GPS coordinates an asset was captured at, in decimal degrees
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
 // Asset represents a photo/video asset from an iPhone backup
 type Asset struct {
-	ID           string     `json:"id"`
-	SourcePath   string     `json:"source_path"`
-	Filename     string     `json:"filename"`
-	Type         AssetType  `json:"type"`
-	CreationDate time.Time  `json:"creation_date"`
-	ModifiedDate time.Time  `json:"modified_date"`
-	Flags        AssetFlags `json:"flags"`
-	FileSize     int64      `json:"file_size"`
-	Checksum     string     `json:"checksum,omitempty"`
-	MimeType     string     `json:"mime_type"`
-	TargetPath   string     `json:"target_path,omitempty"`
+	ID             string     `json:"id"`                         // ZASSET.Z_PK, renumbered when the library is rebuilt
+	UUID           string     `json:"uuid,omitempty"`             // ZASSET.ZUUID, stable across backups of the same library
+	CloudAssetGUID string     `json:"cloud_asset_guid,omitempty"` // ZASSET.ZCLOUDASSETGUID, set for iCloud Photos libraries
+	SourcePath     string     `json:"source_path"`
+	Filename       string     `json:"filename"`
+	Type           AssetType  `json:"type"`
+	CreationDate   time.Time  `json:"creation_date"`
+	ModifiedDate   time.Time  `json:"modified_date"`
+	Flags          AssetFlags `json:"flags"`
+	FileSize       int64      `json:"file_size"`
+	Checksum       string     `json:"checksum,omitempty"`
+	MimeType       string     `json:"mime_type"`
+	TargetPath     string     `json:"target_path,omitempty"`
+	Albums         []string   `json:"albums,omitempty"`
+	People         []string   `json:"people,omitempty"` // named people and pets recognized in the asset
+
+	// Capture location (ZASSET.ZLATITUDE/ZLONGITUDE), and the place it resolves to in the offline gazetteer
+	Location *Location `json:"location,omitempty"`
+	City     string    `json:"city,omitempty"`
+	Country  string    `json:"country,omitempty"`
+
+	// Path of the file on the device (Manifest.db relativePath, e.g. Media/DCIM/100APPLE/IMG_0001.HEIC),
+	// only set when SourcePath is a file of a hashed backup rather than an extracted one
+	DevicePath string `json:"device_path,omitempty"`
+
+	// Filename the asset was captured or imported with (ZADDITIONALASSETATTRIBUTES.ZORIGINALFILENAME)
+	OriginalFilename string `json:"original_filename,omitempty"`
+
+	// Capture timezone (only set when the Photos database records it); CreationDate is expressed in this zone
+	TimezoneName   string `json:"timezone_name,omitempty"`
+	TimezoneOffset *int   `json:"timezone_offset,omitempty"` // seconds east of UTC
+
+	// Live Photo companion video (only set for Live Photos whose paired video was found)
+	LivePhotoVideoPath string `json:"live_photo_video_path,omitempty"`
+	LivePhotoVideoSize int64  `json:"live_photo_video_size,omitempty"`
+
+	// Edited render (FullSizeRender under PhotoData/Mutations, only set for edited assets whose render was found)
+	EditedPath     string `json:"edited_path,omitempty"`
+	EditedFilename string `json:"edited_filename,omitempty"` // device filename, when EditedPath doesn't carry it
+	EditedSize     int64  `json:"edited_size,omitempty"`
+	EditedMimeType string `json:"edited_mime_type,omitempty"`
 }
 
+// NoAlbumFolder is the folder used for assets that don't belong to any album
+// when organizing the remote by album
+const NoAlbumFolder = "No Album"
+
+// NoPersonFolder is used for the {person} path template token when no named person is recognized in the asset
+const NoPersonFolder = "No Person"
+
+// UnknownCountryFolder and UnknownCityFolder are used for the {country} and {city} path template
+// tokens when the asset has no location or it isn't near a known place
+const (
+	UnknownCountryFolder = "Unknown Country"
+	UnknownCityFolder    = "Unknown City"
+)
+
 // ShouldExclude determines if an asset should be excluded based on default rules
 func (a *Asset) ShouldExclude(includeHidden, includeRecentlyDeleted bool) bool {
 	if a.Flags.Hidden && !includeHidden {
@@ -74,24 +223,435 @@ const (
 // Default behavior (day granularity): YYYY/MM/DD/<type>/filename
 // Month granularity: YYYY/MM/<type>/filename
 // Year granularity: YYYY/<type>/filename
-func (a *Asset) GenerateTargetPath(granularity PathGranularity) string {
+// Subtypes use the folder of their base type, and burst frames stay directly in the burst folder
+// (the {burst} path template token groups them), so newly detected details never move synced assets
+// When filenameTemplate is non-empty the filename is rendered from it (see RenderFilename)
+func (a *Asset) GenerateTargetPath(granularity PathGranularity, filenameTemplate string) string {
 	year := a.CreationDate.Format("2006")
 	month := a.CreationDate.Format("01")
 	day := a.CreationDate.Format("02")
 
+	filename := a.Filename
+	if filenameTemplate != "" {
+		filename = a.RenderFilename(filenameTemplate)
+	}
+
+	folder := string(a.Type.Base())
+
 	var p string
 	switch granularity {
 	case GranularityYear:
-		p = path.Join(year, string(a.Type), a.Filename)
+		p = path.Join(year, folder, filename)
 	case GranularityMonth:
-		p = path.Join(year, month, string(a.Type), a.Filename)
+		p = path.Join(year, month, folder, filename)
 	default: // day granularity
-		p = path.Join(year, month, day, string(a.Type), a.Filename)
+		p = path.Join(year, month, day, folder, filename)
 	}
 	// path.Join already returns forward slashes
 	return p
 }
 
+// SetCaptureTimezone records the timezone the asset was captured in and expresses CreationDate in it
+// so date folders and date filters follow the local calendar day of the capture
+func (a *Asset) SetCaptureTimezone(name string, offsetSeconds int) {
+	if name == "" {
+		sign := "+"
+		abs := offsetSeconds
+		if abs < 0 {
+			sign, abs = "-", -abs
+		}
+		name = fmt.Sprintf("UTC%s%02d:%02d", sign, abs/3600, (abs%3600)/60)
+	}
+	a.TimezoneName = name
+	a.TimezoneOffset = &offsetSeconds
+	a.CreationDate = a.CreationDate.In(time.FixedZone(name, offsetSeconds))
+}
+
+// ApplyDefaultTimezone expresses CreationDate in loc for assets without a recorded capture timezone
+func (a *Asset) ApplyDefaultTimezone(loc *time.Location) {
+	if loc == nil || a.TimezoneOffset != nil {
+		return
+	}
+	a.CreationDate = a.CreationDate.In(loc)
+}
+
+// CaptureDay returns the calendar day the asset was captured on (in its own timezone) as midnight UTC,
+// suitable for comparing against dates parsed from YYYY-MM-DD filters
+func (a *Asset) CaptureDay() time.Time {
+	year, month, day := a.CreationDate.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// DefaultFilenameDateLayout is used by the {date} filename template token when no layout is given
+const DefaultFilenameDateLayout = "20060102_150405"
+
+// filenameTemplateTokens lists the tokens supported by filename templates (besides {date:<layout>})
+var filenameTemplateTokens = map[string]bool{
+	"original": true, // original filename with extension, falls back to the stored filename
+	"base":     true, // original filename without extension
+	"filename": true, // stored filename (ZFILENAME), e.g. IMG_4821.HEIC
+	"ext":      true, // extension of the stored file without the dot
+	"id":       true, // stable asset ID (see StableID), so names survive a library rebuild
+	"date":     true, // creation date formatted with DefaultFilenameDateLayout
+}
+
+// UnknownDeviceFolder is used for the {device} path template token when the device name isn't known
+const UnknownDeviceFolder = "Unknown Device"
+
+// pathTemplateTokens lists the tokens supported by path templates (besides {date:<layout>})
+var pathTemplateTokens = map[string]bool{
+	"year":      true, // 2024
+	"month":     true, // 03
+	"day":       true, // 18
+	"monthname": true, // March
+	"type":      true, // asset type folder, e.g. photos (subtypes use their base type)
+	"subtype":   true, // media subtype, e.g. portrait (no folder for assets without one)
+	"device":    true, // device name from the backup
+	"album":     true, // primary album (NoAlbumFolder when not in an album)
+	"person":    true, // primary person (NoPersonFolder when nobody named is recognized)
+	"country":   true, // country of the capture location (UnknownCountryFolder when unknown)
+	"city":      true, // nearest city to the capture location (UnknownCityFolder when unknown)
+	"burst":     true, // burst ID shared by the frames of a burst (no folder outside bursts)
+	"original":  true, // original filename with extension
+	"filename":  true, // upload filename (after --filename-template)
+	"date":      true, // creation date formatted with a Go time layout
+}
+
+// PathTemplateOptions carries the values a path template needs besides the asset itself
+type PathTemplateOptions struct {
+	DeviceName       string
+	PreferredAlbums  []string
+	PreferredPeople  []string
+	FilenameTemplate string
+}
+
+// ValidatePathTemplate checks that a path template only uses known tokens
+// Tokens: {year}, {month}, {day}, {monthname}, {type}, {subtype}, {device}, {album}, {person}, {country}, {city}, {burst},
+// {original}, {filename}, {date:<Go time layout>}
+func ValidatePathTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("path template is empty")
+	}
+	_, err := expandTemplate(template, func(token string) (string, bool) {
+		name, _, _ := strings.Cut(token, ":")
+		return "", pathTemplateTokens[name]
+	})
+	return err
+}
+
+// GenerateTemplatedTargetPath creates the target path from a path template such as
+// "{year}/{year}-{month} {monthname}/{filename}". Each token expands within a single folder level;
+// only literal "/" in the template creates folders. The filename is appended when the template
+// doesn't contain {filename} or {original}.
+func (a *Asset) GenerateTemplatedTargetPath(template string, opts PathTemplateOptions) string {
+	filename := a.Filename
+	if opts.FilenameTemplate != "" {
+		filename = a.RenderFilename(opts.FilenameTemplate)
+	}
+	original := a.OriginalFilename
+	if original == "" {
+		original = a.Filename
+	}
+
+	hasFilename := false
+	rendered, err := expandTemplate(template, func(token string) (string, bool) {
+		name, layout, _ := strings.Cut(token, ":")
+		switch name {
+		case "year":
+			return a.CreationDate.Format("2006"), true
+		case "month":
+			return a.CreationDate.Format("01"), true
+		case "day":
+			return a.CreationDate.Format("02"), true
+		case "monthname":
+			return a.CreationDate.Month().String(), true
+		case "type":
+			return string(a.Type.Base()), true
+		case "subtype":
+			return a.Type.Subtype(), true
+		case "device":
+			return SanitizePathSegment(opts.DeviceName, UnknownDeviceFolder), true
+		case "album":
+			return SanitizePathSegment(a.PrimaryAlbum(opts.PreferredAlbums), NoAlbumFolder), true
+		case "person":
+			return SanitizePathSegment(a.PrimaryPerson(opts.PreferredPeople), NoPersonFolder), true
+		case "country":
+			return SanitizePathSegment(a.Country, UnknownCountryFolder), true
+		case "city":
+			return SanitizePathSegment(a.City, UnknownCityFolder), true
+		case "burst":
+			return a.BurstFolder(), true
+		case "original":
+			hasFilename = true
+			return SanitizePathSegment(original, a.Filename), true
+		case "filename":
+			hasFilename = true
+			return filename, true
+		case "date":
+			if layout == "" {
+				layout = DefaultFilenameDateLayout
+			}
+			return SanitizePathSegment(a.CreationDate.Format(layout), ""), true
+		}
+		return "", false
+	})
+	if err != nil {
+		return a.GenerateTargetPath(GranularityDay, opts.FilenameTemplate)
+	}
+
+	// Drop empty folder levels (e.g. from "//" or a leading "/")
+	var segments []string
+	for _, segment := range strings.Split(rendered, "/") {
+		if segment = strings.TrimSpace(segment); segment != "" && segment != "." && segment != ".." {
+			segments = append(segments, segment)
+		}
+	}
+	if !hasFilename {
+		segments = append(segments, filename)
+	}
+	return path.Join(segments...)
+}
+
+// ValidateFilenameTemplate checks that a filename template only uses known tokens
+// Tokens: {original}, {base}, {filename}, {ext}, {id}, {date} and {date:<Go time layout>}
+func ValidateFilenameTemplate(template string) error {
+	_, err := expandTemplate(template, func(token string) (string, bool) {
+		name, _, _ := strings.Cut(token, ":")
+		return "", filenameTemplateTokens[name]
+	})
+	return err
+}
+
+// RenderFilename renders the upload filename from a template such as "{date:20060102_150405}_{original}"
+// The result only depends on the asset's metadata so re-runs produce the same name. The stored file's
+// extension is appended when the rendered name doesn't already end with it.
+func (a *Asset) RenderFilename(template string) string {
+	original := a.OriginalFilename
+	if original == "" {
+		original = a.Filename
+	}
+	ext := filepath.Ext(a.Filename)
+
+	rendered, err := expandTemplate(template, func(token string) (string, bool) {
+		name, layout, hasLayout := strings.Cut(token, ":")
+		switch name {
+		case "original":
+			return original, true
+		case "base":
+			return strings.TrimSuffix(original, filepath.Ext(original)), true
+		case "filename":
+			return a.Filename, true
+		case "ext":
+			return strings.TrimPrefix(ext, "."), true
+		case "id":
+			return SanitizePathSegment(a.StableID(), a.ID), true
+		case "date":
+			if !hasLayout || layout == "" {
+				layout = DefaultFilenameDateLayout
+			}
+			return a.CreationDate.Format(layout), true
+		}
+		return "", false
+	})
+	if err != nil {
+		return a.Filename
+	}
+
+	rendered = SanitizePathSegment(rendered, "")
+	if rendered == "" {
+		return a.Filename
+	}
+	if ext != "" && !strings.EqualFold(filepath.Ext(rendered), ext) {
+		rendered += ext
+	}
+	return rendered
+}
+
+// expandTemplate replaces {token} placeholders using resolve, which reports whether a token is known
+func expandTemplate(template string, resolve func(token string) (string, bool)) (string, error) {
+	var b strings.Builder
+	rest := template
+	for {
+		start := strings.Index(rest, "{")
+		if start == -1 {
+			if strings.Contains(rest, "}") {
+				return "", fmt.Errorf("unmatched '}' in template '%s'", template)
+			}
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+
+		end := strings.Index(rest[start:], "}")
+		if end == -1 {
+			return "", fmt.Errorf("unclosed '{' in template '%s'", template)
+		}
+		if strings.Contains(rest[:start], "}") {
+			return "", fmt.Errorf("unmatched '}' in template '%s'", template)
+		}
+
+		token := rest[start+1 : start+end]
+		value, ok := resolve(token)
+		if !ok {
+			return "", fmt.Errorf("unknown token '{%s}' in template '%s'", token, template)
+		}
+
+		b.WriteString(rest[:start])
+		b.WriteString(value)
+		rest = rest[start+end+1:]
+	}
+}
+
+// HasLivePhotoVideo reports whether the asset is a Live Photo with a resolved companion video
+func (a *Asset) HasLivePhotoVideo() bool {
+	return a.Type == AssetTypeLivePhoto && a.LivePhotoVideoPath != ""
+}
+
+// LivePhotoVideoCandidates returns the possible companion video filenames for a Live Photo still
+// The paired video shares the still's base name in the same DCIM directory (e.g. IMG_0001.HEIC -> IMG_0001.MOV)
+func LivePhotoVideoCandidates(stillFilename string) []string {
+	base := strings.TrimSuffix(stillFilename, filepath.Ext(stillFilename))
+	return []string{base + ".MOV", base + ".mov"}
+}
+
+// LivePhotoVideoTargetPath returns the remote path for a Live Photo's video, placed next to the still
+// and renamed to the still's base name so both halves stay paired (e.g. .../IMG_0001.HEIC -> .../IMG_0001.MOV)
+func LivePhotoVideoTargetPath(stillTargetPath, videoFilename string) string {
+	dir := path.Dir(stillTargetPath)
+	base := strings.TrimSuffix(path.Base(stillTargetPath), path.Ext(stillTargetPath))
+	return path.Join(dir, base+filepath.Ext(videoFilename))
+}
+
+// StableID returns the identity used to match the asset across backups: its UUID, or its
+// Photos database primary key when the schema has no UUID
+func (a *Asset) StableID() string {
+	if a.UUID != "" {
+		return a.UUID
+	}
+	return a.ID
+}
+
+// LivePhotoVideoFilename returns the device filename of the paired video of a Live Photo
+func (a *Asset) LivePhotoVideoFilename() string {
+	if a.Flags.LivePhotoVideoID != nil {
+		return *a.Flags.LivePhotoVideoID
+	}
+	return filepath.Base(a.LivePhotoVideoPath)
+}
+
+// EditedRenderFilename returns the device filename of the edited render
+func (a *Asset) EditedRenderFilename() string {
+	if a.EditedFilename != "" {
+		return a.EditedFilename
+	}
+	return filepath.Base(a.EditedPath)
+}
+
+// MatchPath returns the path ignore patterns are matched against: the device path for
+// assets read from a hashed backup, otherwise the source path
+func (a *Asset) MatchPath() string {
+	if a.DevicePath != "" {
+		return filepath.FromSlash(a.DevicePath)
+	}
+	return a.SourcePath
+}
+
+// HasEditedRender reports whether the asset was edited on the device and its rendered result was found
+func (a *Asset) HasEditedRender() bool {
+	return a.Flags.Edited && a.EditedPath != ""
+}
+
+// EditedTargetPath returns the remote path for an edited render, placed next to the original
+// with EditedSuffix added to the base name (e.g. .../IMG_0001.HEIC -> .../IMG_0001_edited.jpg)
+func EditedTargetPath(originalTargetPath, renderFilename string) string {
+	dir := path.Dir(originalTargetPath)
+	base := strings.TrimSuffix(path.Base(originalTargetPath), path.Ext(originalTargetPath))
+	return path.Join(dir, base+EditedSuffix+filepath.Ext(renderFilename))
+}
+
+// InAnyAlbum reports whether the asset belongs to at least one of the given albums (case-insensitive)
+func (a *Asset) InAnyAlbum(albums []string) bool {
+	for _, wanted := range albums {
+		for _, album := range a.Albums {
+			if strings.EqualFold(strings.TrimSpace(wanted), album) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PrimaryAlbum returns the album used to place the asset in an album-based layout.
+// The first preferred album the asset belongs to wins; otherwise the asset's first album is used.
+// Returns an empty string if the asset isn't in any album.
+func (a *Asset) PrimaryAlbum(preferred []string) string {
+	for _, wanted := range preferred {
+		for _, album := range a.Albums {
+			if strings.EqualFold(strings.TrimSpace(wanted), album) {
+				return album
+			}
+		}
+	}
+	if len(a.Albums) > 0 {
+		return a.Albums[0]
+	}
+	return ""
+}
+
+// HasAnyPerson reports whether at least one of the given people is recognized in the asset (case-insensitive)
+func (a *Asset) HasAnyPerson(people []string) bool {
+	for _, wanted := range people {
+		for _, person := range a.People {
+			if strings.EqualFold(strings.TrimSpace(wanted), person) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PrimaryPerson returns the person used to place the asset in a person-based layout.
+// The first preferred person recognized in the asset wins; otherwise the asset's first person is used.
+// Returns an empty string if nobody named is recognized in the asset.
+func (a *Asset) PrimaryPerson(preferred []string) string {
+	for _, wanted := range preferred {
+		for _, person := range a.People {
+			if strings.EqualFold(strings.TrimSpace(wanted), person) {
+				return person
+			}
+		}
+	}
+	if len(a.People) > 0 {
+		return a.People[0]
+	}
+	return ""
+}
+
+// BurstFolder returns the folder the frames of the asset's burst are grouped in, or an empty string
+// if the asset isn't part of a burst
+func (a *Asset) BurstFolder() string {
+	if a.Flags.BurstID == nil {
+		return ""
+	}
+	return SanitizePathSegment(*a.Flags.BurstID, "")
+}
+
+// GenerateAlbumTargetPath creates the target path prefixed with the asset's primary album folder
+// Example (day granularity): <album>/YYYY/MM/DD/<type>/filename
+func (a *Asset) GenerateAlbumTargetPath(granularity PathGranularity, filenameTemplate string, preferredAlbums []string) string {
+	return path.Join(SanitizePathSegment(a.PrimaryAlbum(preferredAlbums), NoAlbumFolder), a.GenerateTargetPath(granularity, filenameTemplate))
+}
+
+// SanitizePathSegment makes a user-provided name (album, person, etc.) safe to use as a single path segment
+// Path separators are replaced and surrounding whitespace/dots are trimmed. Returns fallback if nothing is left.
+func SanitizePathSegment(name, fallback string) string {
+	name = strings.NewReplacer("/", "-", "\\", "-").Replace(name)
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
 // ComputeChecksum calculates SHA256 checksum of the asset file
 func (a *Asset) ComputeChecksum() error {
 	if a.SourcePath == "" {
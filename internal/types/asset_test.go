@@ -1,6 +1,9 @@
 package types
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestAssetShouldExclude(t *testing.T) {
 	tests := []struct {
@@ -78,3 +81,277 @@ func TestClassifyByExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestAssetTypeSubtypes(t *testing.T) {
+	tests := []struct {
+		assetType AssetType
+		base      AssetType
+		matches   []string
+		excludes  []string
+	}{
+		{AssetTypePortrait, AssetTypePhoto, []string{"portrait", "photos", "PHOTOS"}, []string{"videos", "panorama"}},
+		{AssetTypeRaw, AssetTypePhoto, []string{"raw", "photos"}, []string{"videos"}},
+		{AssetTypeSlomo, AssetTypeVideo, []string{"slomo", "videos"}, []string{"photos", "timelapse"}},
+		{AssetTypeScreenRecording, AssetTypeVideo, []string{"screen_recording", "videos"}, []string{"screenshots"}},
+		{AssetTypePhoto, AssetTypePhoto, []string{"photos"}, []string{"portrait"}},
+		{AssetTypeLivePhoto, AssetTypeLivePhoto, []string{"live_photos"}, []string{"photos"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.assetType), func(t *testing.T) {
+			if base := tt.assetType.Base(); base != tt.base {
+				t.Errorf("Base() = %v, want %v", base, tt.base)
+			}
+			for _, name := range tt.matches {
+				if !tt.assetType.Matches(name) {
+					t.Errorf("expected %v to match %q", tt.assetType, name)
+				}
+			}
+			for _, name := range tt.excludes {
+				if tt.assetType.Matches(name) {
+					t.Errorf("expected %v not to match %q", tt.assetType, name)
+				}
+			}
+		})
+	}
+
+	if !AssetTypeCinematic.IsVideo() || AssetTypePanorama.IsVideo() {
+		t.Errorf("expected cinematic to be a video and panorama not to be")
+	}
+
+	// Subtypes keep their base type's folder; only {subtype} separates them
+	asset := &Asset{Filename: "IMG_0005.MOV", Type: AssetTypeSlomo, CreationDate: time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)}
+	if result := asset.GenerateTargetPath(GranularityDay, ""); result != "2024/03/18/videos/IMG_0005.MOV" {
+		t.Errorf("GenerateTargetPath() = %v, want 2024/03/18/videos/IMG_0005.MOV", result)
+	}
+	if result := asset.GenerateTemplatedTargetPath("{year}/{type}/{subtype}", PathTemplateOptions{}); result != "2024/videos/slomo/IMG_0005.MOV" {
+		t.Errorf("GenerateTemplatedTargetPath() = %v, want 2024/videos/slomo/IMG_0005.MOV", result)
+	}
+	asset.Type = AssetTypeVideo
+	if result := asset.GenerateTemplatedTargetPath("{year}/{type}/{subtype}", PathTemplateOptions{}); result != "2024/videos/IMG_0005.MOV" {
+		t.Errorf("GenerateTemplatedTargetPath() = %v, want 2024/videos/IMG_0005.MOV", result)
+	}
+}
+
+func TestAssetPrimaryAlbum(t *testing.T) {
+	tests := []struct {
+		name      string
+		albums    []string
+		preferred []string
+		expected  string
+	}{
+		{"no albums", nil, nil, ""},
+		{"first album without preference", []string{"Family", "Trips"}, nil, "Family"},
+		{"preferred album wins", []string{"Family", "Trips"}, []string{"trips"}, "Trips"},
+		{"preference not matched falls back", []string{"Family"}, []string{"Work"}, "Family"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset := Asset{Albums: tt.albums}
+			if result := asset.PrimaryAlbum(tt.preferred); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGenerateAlbumTargetPath(t *testing.T) {
+	asset := Asset{
+		Filename:     "IMG_0001.HEIC",
+		Type:         AssetTypePhoto,
+		CreationDate: time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC),
+		Albums:       []string{"Trips/2024"},
+	}
+
+	if result := asset.GenerateAlbumTargetPath(GranularityMonth, "", nil); result != "Trips-2024/2024/03/photos/IMG_0001.HEIC" {
+		t.Errorf("unexpected album target path: %s", result)
+	}
+
+	asset.Albums = nil
+	if result := asset.GenerateAlbumTargetPath(GranularityYear, "", nil); result != "No Album/2024/photos/IMG_0001.HEIC" {
+		t.Errorf("unexpected target path for asset without album: %s", result)
+	}
+}
+
+func TestBurstTargetPath(t *testing.T) {
+	burstID := "0C1B5E7A-8F2D-4C3B-9A61-D5E4F3A2B1C0"
+	asset := Asset{
+		Filename:     "IMG_0002.JPG",
+		Type:         AssetTypeBurst,
+		CreationDate: time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC),
+		Flags:        AssetFlags{Burst: true, BurstID: &burstID},
+	}
+
+	// The default layout keeps frames where syncs before burst IDs were read put them
+	if result := asset.GenerateTargetPath(GranularityDay, ""); result != "2024/03/18/burst/IMG_0002.JPG" {
+		t.Errorf("unexpected burst target path: %s", result)
+	}
+	if result := asset.GenerateTemplatedTargetPath("{year}/{burst}", PathTemplateOptions{}); result != "2024/"+burstID+"/IMG_0002.JPG" {
+		t.Errorf("unexpected templated burst target path: %s", result)
+	}
+
+	// Outside bursts the {burst} token adds no folder
+	asset.Flags = AssetFlags{}
+	if result := asset.GenerateTemplatedTargetPath("{year}/{burst}", PathTemplateOptions{}); result != "2024/IMG_0002.JPG" {
+		t.Errorf("unexpected templated target path outside a burst: %s", result)
+	}
+}
+
+func TestLivePhotoVideoTargetPath(t *testing.T) {
+	got := LivePhotoVideoTargetPath("2024/03/18/live_photos/IMG_0001.HEIC", "/backup/DCIM/100APPLE/IMG_0001.MOV")
+	if got != "2024/03/18/live_photos/IMG_0001.MOV" {
+		t.Errorf("LivePhotoVideoTargetPath() = %q, want %q", got, "2024/03/18/live_photos/IMG_0001.MOV")
+	}
+
+	candidates := LivePhotoVideoCandidates("IMG_0001.HEIC")
+	if len(candidates) != 2 || candidates[0] != "IMG_0001.MOV" || candidates[1] != "IMG_0001.mov" {
+		t.Errorf("LivePhotoVideoCandidates() = %v", candidates)
+	}
+}
+
+func TestRenderFilename(t *testing.T) {
+	asset := &Asset{
+		ID:               "42",
+		Filename:         "IMG_4821.HEIC",
+		Type:             AssetTypePhoto,
+		OriginalFilename: "DSC01234.HEIC",
+		CreationDate:     time.Date(2024, 3, 18, 21, 15, 2, 500000000, time.FixedZone("GMT-0600", -6*3600)),
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "{date:20060102_150405}_{original}", expected: "20240318_211502_DSC01234.HEIC"},
+		{template: "{date}_{id}", expected: "20240318_211502_42.HEIC"},
+		{template: "{base}-{filename}", expected: "DSC01234-IMG_4821.HEIC"},
+		{template: "{date:2006/01/02}", expected: "2024-03-18.HEIC"},
+	}
+
+	for _, tt := range tests {
+		if result := asset.RenderFilename(tt.template); result != tt.expected {
+			t.Errorf("RenderFilename(%q) = %q, want %q", tt.template, result, tt.expected)
+		}
+	}
+
+	// {id} is the UUID when the schema has one, since Z_PK changes when the library is rebuilt
+	withUUID := *asset
+	withUUID.UUID = "5A3F1C2B-0D4E-4F6A-8B9C-1D2E3F4A5B6C"
+	if result := withUUID.RenderFilename("{id}"); result != "5A3F1C2B-0D4E-4F6A-8B9C-1D2E3F4A5B6C.HEIC" {
+		t.Errorf("RenderFilename({id}) = %q", result)
+	}
+
+	// Deterministic: rendering twice yields the same name
+	if asset.RenderFilename("{date}_{original}") != asset.RenderFilename("{date}_{original}") {
+		t.Error("RenderFilename should be deterministic")
+	}
+
+	// Falls back to the stored filename when the original is unknown
+	asset.OriginalFilename = ""
+	if result := asset.GenerateTargetPath(GranularityDay, "{original}"); result != "2024/03/18/photos/IMG_4821.HEIC" {
+		t.Errorf("GenerateTargetPath() with template = %q", result)
+	}
+}
+
+func TestValidateFilenameTemplate(t *testing.T) {
+	valid := []string{"{original}", "{date:20060102}_{base}.{ext}", "prefix_{id}"}
+	for _, template := range valid {
+		if err := ValidateFilenameTemplate(template); err != nil {
+			t.Errorf("ValidateFilenameTemplate(%q) returned error: %v", template, err)
+		}
+	}
+
+	invalid := []string{"{unknown}", "{original", "original}", "{date:2006}_{nope}"}
+	for _, template := range invalid {
+		if err := ValidateFilenameTemplate(template); err == nil {
+			t.Errorf("ValidateFilenameTemplate(%q) expected error", template)
+		}
+	}
+}
+
+func TestGenerateTemplatedTargetPath(t *testing.T) {
+	asset := &Asset{
+		ID:               "1",
+		Filename:         "IMG_0001.HEIC",
+		OriginalFilename: "DSC01234.HEIC",
+		Type:             AssetTypePhoto,
+		CreationDate:     time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC),
+		Albums:           []string{"Trips/2024"},
+		People:           []string{"Biscuit", "Grandma"},
+		Country:          "United States",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		opts     PathTemplateOptions
+		expected string
+	}{
+		{
+			name:     "archive layout without type folder",
+			template: "{year}/{year}-{month} {monthname}/{filename}",
+			expected: "2024/2024-03 March/IMG_0001.HEIC",
+		},
+		{
+			name:     "filename appended when missing",
+			template: "{device}/{type}",
+			opts:     PathTemplateOptions{DeviceName: "Jane's iPhone"},
+			expected: "Jane's iPhone/photos/IMG_0001.HEIC",
+		},
+		{
+			name:     "token values never create folders",
+			template: "{album}/{date:2006/01}/{original}",
+			expected: "Trips-2024/2024-03/DSC01234.HEIC",
+		},
+		{
+			name:     "preferred person",
+			template: "People/{person}/{year}",
+			opts:     PathTemplateOptions{PreferredPeople: []string{"grandma"}},
+			expected: "People/Grandma/2024/IMG_0001.HEIC",
+		},
+		{
+			name:     "places",
+			template: "Places/{country}/{city}",
+			expected: "Places/United States/Unknown City/IMG_0001.HEIC",
+		},
+		{
+			name:     "fallbacks and filename template",
+			template: "/{device}//{day}/{filename}",
+			opts:     PathTemplateOptions{FilenameTemplate: "{date:150405}_{original}"},
+			expected: "Unknown Device/18/100000_DSC01234.HEIC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := asset.GenerateTemplatedTargetPath(tt.template, tt.opts); result != tt.expected {
+				t.Errorf("GenerateTemplatedTargetPath(%q) = %q, want %q", tt.template, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasAnyPerson(t *testing.T) {
+	asset := &Asset{People: []string{"Biscuit", "Grandma"}}
+	if !asset.HasAnyPerson([]string{"Uncle Bob", " grandma "}) {
+		t.Errorf("expected a case-insensitive match for grandma")
+	}
+	if asset.HasAnyPerson([]string{"Uncle Bob"}) {
+		t.Errorf("expected no match for Uncle Bob")
+	}
+	if got := (&Asset{}).GenerateTemplatedTargetPath("{person}/{filename}", PathTemplateOptions{}); got != "No Person" {
+		t.Errorf("expected the No Person folder for assets without people, got %q", got)
+	}
+}
+
+func TestValidatePathTemplate(t *testing.T) {
+	if err := ValidatePathTemplate("{year}/{year}-{month} {monthname}/{filename}"); err != nil {
+		t.Errorf("ValidatePathTemplate returned error for valid template: %v", err)
+	}
+	for _, template := range []string{"", "{year}/{week}", "{year/{month}"} {
+		if err := ValidatePathTemplate(template); err == nil {
+			t.Errorf("ValidatePathTemplate(%q) expected error", template)
+		}
+	}
+}
@@ -11,10 +11,13 @@ import (
 	"github.com/fatih/color"
 	"github.com/grantbirki/gh-photos/internal/audit"
 	"github.com/grantbirki/gh-photos/internal/backup"
+	"github.com/grantbirki/gh-photos/internal/diff"
+	"github.com/grantbirki/gh-photos/internal/geo"
 	"github.com/grantbirki/gh-photos/internal/logger"
 	"github.com/grantbirki/gh-photos/internal/manifest"
 	"github.com/grantbirki/gh-photos/internal/rclone"
 	"github.com/grantbirki/gh-photos/internal/types"
+	"github.com/grantbirki/gh-photos/internal/utils"
 	"github.com/grantbirki/gh-photos/internal/version"
 )
 
@@ -40,18 +43,48 @@ type Config struct {
 	SaveAuditManifest      string
 	UseLastCommand         bool
 	BatchTimeout           time.Duration
+	Albums                 []string
+	People                 []string // only sync assets in which one of these people or pets is recognized
+	OrganizeByAlbum        bool
+	LivePhotoMode          string
+	BurstMode              string // which frames of a burst to sync: all, picked, or key
+	EditsMode              string
+	Timezone               string // default timezone for assets without a recorded capture timezone
+	FilenameTemplate       string // template for remote filenames, e.g. "{date:20060102_150405}_{original}"
+	PathTemplate           string // template for remote paths, replaces PathGranularity when set
+	DeviceName             string // device name from the backup, used by the {device} path template token
+	Resume                 string // manifest from an interrupted sync; only entries not yet uploaded or verified are retried
+	RemotePreScan          bool   // list the remote while planning so existing files are marked as skips
+	Password               string // password of an encrypted backup; never written to manifests
+	AssetList              string // JSON from `gh photos diff`; only its added and edited assets are synced
+	Near                   string // "lat,lon,radius"; only sync assets captured within this area
+	Gazetteer              string // GeoNames cities file used instead of the embedded gazetteer
+	FavoritesOnly          bool   // only sync assets marked as favorites
+	FavoritesRemote        string // second remote that favorites are mirrored to after the main upload
 }
 
+// checkpointInterval limits how often the manifest is rewritten while uploads are in progress
+const checkpointInterval = 5 * time.Second
+
+// encryptedChunkSize is the number of files of an encrypted backup that are decrypted at a time
+const encryptedChunkSize = 100
+
 // Uploader orchestrates the photo backup process
 type Uploader struct {
 	config          Config
 	logger          *logger.Logger
 	parser          *backup.BackupParser
 	rcloneClient    *rclone.Client
+	favoritesClient *rclone.Client // nil unless FavoritesRemote is set
 	manifest        *manifest.Manifest
 	auditTrail      *audit.TrailManager
 	filteredAssets  []*types.Asset // Store filtered assets for audit trail
 	uploadStartTime time.Time      // Track upload start time for ETA calculations
+	uploadIndexes   []int          // Manifest index of each entry passed to UploadBatch
+	lastCheckpoint  time.Time      // When the manifest checkpoint was last written
+	checkpointPath  string         // Default checkpoint when neither --save-manifest nor --resume is set
+
+	selection map[string]bool // stable IDs selected by --asset-list; nil selects every asset
 }
 
 // CreateUploader creates a new uploader instance
@@ -81,10 +114,19 @@ func CreateUploader(config Config) (*Uploader, error) {
 		if err := rclone.ValidateRemoteAuthentication(config.Remote, logger); err != nil {
 			return nil, fmt.Errorf("remote authentication failed: %w", err)
 		}
+
+		if config.FavoritesRemote != "" {
+			if err := rclone.ValidateRemote(config.FavoritesRemote, logger); err != nil {
+				return nil, fmt.Errorf("favorites remote validation failed: %w", err)
+			}
+			if err := rclone.ValidateRemoteAuthentication(config.FavoritesRemote, logger); err != nil {
+				return nil, fmt.Errorf("favorites remote authentication failed: %w", err)
+			}
+		}
 	}
 
 	// Create backup parser
-	parser, err := backup.CreateBackupParser(config.BackupPath, logger)
+	parser, err := backup.CreateBackupParserWithPassword(config.BackupPath, config.Password, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create backup parser: %w", err)
 	}
@@ -108,6 +150,27 @@ func CreateUploader(config Config) (*Uploader, error) {
 		rcloneClient.SetBatchTimeout(config.BatchTimeout)
 	}
 
+	// Pre-scan the remote when planning if requested
+	rcloneClient.SetRemotePreScan(config.RemotePreScan)
+
+	// Create a second rclone client for the favorites mirror; the main remote's metadata and
+	// pre-scan settings don't apply to it
+	var favoritesClient *rclone.Client
+	if config.FavoritesRemote != "" {
+		favoritesClient = rclone.CreateClient(
+			config.FavoritesRemote,
+			config.Parallel,
+			config.Verify,
+			config.DryRun,
+			config.SkipExisting,
+			logger,
+			config.LogLevel,
+		)
+		if config.BatchTimeout > 0 {
+			favoritesClient.SetBatchTimeout(config.BatchTimeout)
+		}
+	}
+
 	// Create audit trail manager
 	auditTrail, err := audit.CreateTrailManager(version.String())
 	if err != nil {
@@ -115,11 +178,12 @@ func CreateUploader(config Config) (*Uploader, error) {
 	}
 
 	return &Uploader{
-		config:       config,
-		logger:       logger,
-		parser:       parser,
-		rcloneClient: rcloneClient,
-		auditTrail:   auditTrail,
+		config:          config,
+		logger:          logger,
+		parser:          parser,
+		rcloneClient:    rcloneClient,
+		favoritesClient: favoritesClient,
+		auditTrail:      auditTrail,
 	}, nil
 }
 
@@ -146,15 +210,24 @@ func (u *Uploader) Execute(ctx context.Context) error {
 		return err
 	}
 
-	if len(u.filteredAssets) == 0 {
-		u.logInfo("No assets to process. Exiting.")
-		return nil
-	}
+	var plan []rclone.UploadPlanEntry
+	if u.config.Resume != "" {
+		// Continue from the manifest of an interrupted sync
+		plan, err = u.resumeManifestAndPlan(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		if len(u.filteredAssets) == 0 {
+			u.logInfo("No assets to process. Exiting.")
+			return nil
+		}
 
-	// Create manifest and upload plan
-	plan, err := u.createManifestAndPlan(ctx, assets)
-	if err != nil {
-		return err
+		// Create manifest and upload plan
+		plan, err = u.createManifestAndPlan(ctx, assets)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Execute uploads
@@ -162,6 +235,11 @@ func (u *Uploader) Execute(ctx context.Context) error {
 		return err
 	}
 
+	// Mirror favorites to the favorites remote
+	if err := u.mirrorFavorites(ctx); err != nil {
+		return err
+	}
+
 	// Finalize execution
 	duration := time.Since(startTime)
 	return u.finalizeExecution(duration)
@@ -173,6 +251,23 @@ func (u *Uploader) setupExecution() error {
 	u.logInfo("Backup path: %s", u.config.BackupPath)
 	u.logInfo("Remote target: %s", u.config.Remote)
 
+	// Files of encrypted backups are decrypted to a temp directory that doesn't outlive a sync,
+	// so the source paths recorded in a resume manifest no longer exist
+	if u.config.Resume != "" && u.parser.IsEncrypted() {
+		return fmt.Errorf("resuming a sync of an encrypted backup is not supported; extract the backup first")
+	}
+
+	// Without a manifest path, checkpoint to ~/gh-photos/checkpoints so an interrupted sync can still be resumed
+	if u.config.SaveManifest == "" && u.config.Resume == "" && !u.config.DryRun && !u.parser.IsEncrypted() {
+		path, err := defaultCheckpointPath()
+		if err != nil {
+			u.logError("Progress will not be checkpointed: %v", err)
+		} else {
+			u.checkpointPath = path
+			u.logger.Info(fmt.Sprintf("Checkpointing progress to %s (resume an interrupted sync with --resume %s)", path, path))
+		}
+	}
+
 	// Setup audit trail
 	if err := u.setupAuditTrail(); err != nil {
 		return fmt.Errorf("failed to setup audit trail: %w", err)
@@ -181,6 +276,22 @@ func (u *Uploader) setupExecution() error {
 	return nil
 }
 
+// defaultCheckpointPath returns a new checkpoint file under ~/gh-photos/checkpoints
+func defaultCheckpointPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	checkpointDir := filepath.Join(homeDir, "gh-photos", "checkpoints")
+	if err := os.MkdirAll(checkpointDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	name := fmt.Sprintf("sync-%s.json", time.Now().UTC().Format("2006-01-02T15-04-05Z"))
+	return filepath.Join(checkpointDir, name), nil
+}
+
 // parseAndFilterAssets handles asset parsing and filtering
 func (u *Uploader) parseAndFilterAssets() ([]*types.Asset, error) {
 	// Parse assets from backup
@@ -194,6 +305,11 @@ func (u *Uploader) parseAndFilterAssets() ([]*types.Asset, error) {
 	u.logInfo("Asset parsing completed in %v", parseDuration.Round(time.Millisecond))
 	u.logInfo("Found %d total assets", len(assets))
 
+	// A sync restricted to an asset list must not upload everything when the list can't be read
+	if err := u.loadAssetList(); err != nil {
+		return nil, err
+	}
+
 	// Filter assets
 	u.filteredAssets = u.filterAssets(assets)
 	u.logInfo("After filtering: %d assets to process", len(u.filteredAssets))
@@ -223,6 +339,21 @@ func (u *Uploader) createManifestAndPlan(ctx context.Context, assets []*types.As
 		EndDate:                u.config.EndDate,
 		AssetTypes:             u.config.AssetTypes,
 		PathGranularity:        u.config.PathGranularity,
+		Albums:                 u.config.Albums,
+		People:                 u.config.People,
+		OrganizeByAlbum:        u.config.OrganizeByAlbum,
+		LivePhotoMode:          u.config.LivePhotoMode,
+		BurstMode:              u.config.BurstMode,
+		EditsMode:              u.config.EditsMode,
+		Timezone:               u.config.Timezone,
+		FilenameTemplate:       u.config.FilenameTemplate,
+		PathTemplate:           u.config.PathTemplate,
+		RemotePreScan:          u.config.RemotePreScan,
+		AssetList:              u.config.AssetList,
+		Near:                   u.config.Near,
+		Gazetteer:              u.config.Gazetteer,
+		FavoritesOnly:          u.config.FavoritesOnly,
+		FavoritesRemote:        u.config.FavoritesRemote,
 	}
 
 	generator := manifest.CreateGenerator(u.config.BackupPath, u.config.Remote, manifestConfig)
@@ -243,6 +374,51 @@ func (u *Uploader) createManifestAndPlan(ctx context.Context, assets []*types.As
 	return plan, nil
 }
 
+// resumeManifestAndPlan reloads the manifest of an interrupted sync and plans uploads
+// for every entry that is not already uploaded or verified
+func (u *Uploader) resumeManifestAndPlan(ctx context.Context) ([]rclone.UploadPlanEntry, error) {
+	u.logInfo("Resuming from manifest %s", u.config.Resume)
+	m, err := manifest.LoadFromFile(u.config.Resume)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load resume manifest: %w", err)
+	}
+
+	if m.RemoteTarget != "" && m.RemoteTarget != u.config.Remote {
+		return nil, fmt.Errorf("resume manifest was created for remote %s, not %s", m.RemoteTarget, u.config.Remote)
+	}
+	if m.BackupPath != "" && m.BackupPath != u.config.BackupPath {
+		u.logError("Resume manifest was created for backup %s; continuing with %s", m.BackupPath, u.config.BackupPath)
+	}
+
+	u.manifest = m
+	remaining := m.ResetIncomplete()
+	u.logInfo("%d of %d entries already uploaded, %d remaining", len(m.Entries)-remaining, len(m.Entries), remaining)
+
+	var incomplete []manifest.Entry
+	for _, entry := range m.Entries {
+		if !entry.Status.IsComplete() {
+			incomplete = append(incomplete, entry)
+		}
+	}
+	if len(incomplete) == 0 {
+		return nil, nil
+	}
+
+	// Create upload plan
+	u.logInfo("Creating upload plan...")
+	plan, err := u.rcloneClient.CreateUploadPlan(ctx, incomplete)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload plan: %w", err)
+	}
+
+	// Display plan
+	if u.config.DryRun || u.config.Verbose {
+		rclone.PrintUploadPlan(plan)
+	}
+
+	return plan, nil
+}
+
 // executeUploads handles the upload execution process
 func (u *Uploader) executeUploads(ctx context.Context, plan []rclone.UploadPlanEntry) error {
 	// Execute uploads if not dry run
@@ -254,28 +430,42 @@ func (u *Uploader) executeUploads(ctx context.Context, plan []rclone.UploadPlanE
 
 		u.logInfo("Starting uploads...")
 
+		// Map plan entries back to their manifest entries
+		manifestIndexes := make(map[string]int, len(u.manifest.Entries))
+		for i, entry := range u.manifest.Entries {
+			manifestIndexes[entry.SourcePath] = i
+		}
+
 		// Filter plan entries that need uploading
 		var uploadEntries []manifest.Entry
+		u.uploadIndexes = nil
 		for _, planEntry := range plan {
+			index, ok := manifestIndexes[planEntry.Entry.SourcePath]
+			if !ok {
+				continue
+			}
 			if planEntry.Action == rclone.ActionUpload {
 				uploadEntries = append(uploadEntries, planEntry.Entry)
+				u.uploadIndexes = append(u.uploadIndexes, index)
 			} else if planEntry.Action == rclone.ActionSkip {
 				// Update manifest status for skipped entries
-				for i, entry := range u.manifest.Entries {
-					if entry.SourcePath == planEntry.Entry.SourcePath {
-						u.manifest.UpdateEntry(i, manifest.StatusSkipped, "")
-						break
-					}
-				}
+				u.manifest.UpdateEntry(index, manifest.StatusSkipped, "")
 			}
 		}
 
+		// Write an initial checkpoint so an interrupted run can be resumed
+		u.saveCheckpoint(true)
+
 		// Execute uploads with progress reporting
 		if len(uploadEntries) > 0 {
 			u.uploadStartTime = time.Now()
 			u.logInfo("Uploading %d files...", len(uploadEntries))
-			err := u.rcloneClient.UploadBatch(ctx, uploadEntries, u.updateManifestCallback, u.uploadProgressCallback)
+			err := u.uploadBatch(ctx, u.rcloneClient, uploadEntries, u.updateManifestCallback, u.uploadProgressCallback)
 			if err != nil {
+				u.saveCheckpoint(true)
+				if path := u.manifestPath(); path != "" {
+					u.logError("Upload interrupted; progress saved. Resume with: gh photos sync --resume %s", path)
+				}
 				return fmt.Errorf("upload failed: %w", err)
 			}
 
@@ -297,15 +487,134 @@ func (u *Uploader) executeUploads(ctx context.Context, plan []rclone.UploadPlanE
 	return nil
 }
 
+// uploadBatch uploads entries with client. The files of an encrypted backup are decrypted one
+// chunk at a time and deleted once the chunk is uploaded, so a sync never needs temp space
+// for more than encryptedChunkSize files.
+func (u *Uploader) uploadBatch(ctx context.Context, client *rclone.Client, entries []manifest.Entry, updateCallback func(int, manifest.OperationStatus, string), progressCallback rclone.ProgressCallback) error {
+	if u.config.DryRun || !u.parser.IsEncrypted() {
+		return client.UploadBatch(ctx, entries, updateCallback, progressCallback)
+	}
+
+	for start := 0; start < len(entries); start += encryptedChunkSize {
+		end := min(start+encryptedChunkSize, len(entries))
+		chunk := entries[start:end]
+		paths := make([]string, len(chunk))
+		for i, entry := range chunk {
+			paths[i] = entry.SourcePath
+		}
+		if err := u.parser.PrepareFiles(paths); err != nil {
+			return fmt.Errorf("failed to decrypt files: %w", err)
+		}
+
+		// Callbacks report indexes and progress relative to all entries, not to the chunk
+		offset := start
+		err := client.UploadBatch(ctx, chunk, func(i int, status manifest.OperationStatus, errorMsg string) {
+			updateCallback(offset+i, status, errorMsg)
+		}, func(completed, _ int, currentFile string) {
+			if progressCallback != nil {
+				progressCallback(offset+completed, len(entries), currentFile)
+			}
+		})
+		if releaseErr := u.parser.ReleaseFiles(paths); err == nil {
+			err = releaseErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// favoriteIndexes returns the manifest index of every favorite entry that should be mirrored:
+// those not already on the favorites remote whose upload to the main remote didn't fail
+func (u *Uploader) favoriteIndexes() []int {
+	var indexes []int
+	for i, entry := range u.manifest.Entries {
+		if !entry.Flags.Favorite || entry.Status == manifest.StatusFailed || entry.Status == manifest.StatusMissing {
+			continue
+		}
+		if entry.FavoritesStatus == manifest.StatusUploaded || entry.FavoritesStatus == manifest.StatusSkipped {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// mirrorFavorites copies favorite entries to the favorites remote at the same target paths
+func (u *Uploader) mirrorFavorites(ctx context.Context) error {
+	if u.favoritesClient == nil {
+		return nil
+	}
+
+	indexes := u.favoriteIndexes()
+	if len(indexes) == 0 {
+		u.logInfo("No favorites to mirror to %s", u.config.FavoritesRemote)
+		return nil
+	}
+	if u.config.DryRun {
+		u.logInfo("Would mirror %d favorites to %s", len(indexes), u.config.FavoritesRemote)
+		return nil
+	}
+
+	if err := u.favoritesClient.RunStartupConnectivityTest(); err != nil {
+		return fmt.Errorf("favorites remote connectivity test failed: %w", err)
+	}
+
+	entries := make([]manifest.Entry, len(indexes))
+	for i, index := range indexes {
+		entries[i] = u.manifest.Entries[index]
+	}
+
+	u.logInfo("Mirroring %d favorites to %s...", len(entries), u.config.FavoritesRemote)
+	var mirrored, failed int
+	err := u.uploadBatch(ctx, u.favoritesClient, entries, func(i int, status manifest.OperationStatus, errorMsg string) {
+		if i < 0 || i >= len(indexes) {
+			return
+		}
+		entry := &u.manifest.Entries[indexes[i]]
+		entry.FavoritesStatus = status
+		if status == manifest.StatusFailed {
+			failed++
+			u.logError("Failed to mirror favorite %s: %s", filepath.Base(entry.SourcePath), errorMsg)
+		} else {
+			mirrored++
+		}
+		u.saveCheckpoint(false)
+	}, nil)
+	u.saveCheckpoint(true)
+	if err != nil {
+		return fmt.Errorf("favorites mirror failed: %w", err)
+	}
+
+	if failed > 0 {
+		u.logError("Mirrored %d favorites to %s, %d failed", mirrored, u.config.FavoritesRemote, failed)
+	} else {
+		u.logSuccess("Mirrored %d favorites to %s", mirrored, u.config.FavoritesRemote)
+	}
+	return nil
+}
+
 // finalizeExecution handles summary, manifest saving, and audit trail finalization
 func (u *Uploader) finalizeExecution(duration time.Duration) error {
 	// Update manifest summary
 	u.manifest.Summary.DurationSeconds = int64(duration.Seconds())
 
-	// Save manifest if requested
-	if u.config.SaveManifest != "" {
-		u.logInfo("Saving manifest to %s", u.config.SaveManifest)
-		if err := u.manifest.SaveToFile(u.config.SaveManifest); err != nil {
+	// A default checkpoint is only kept while there is something left to resume
+	if path := u.manifestPath(); path != "" && path == u.checkpointPath {
+		if u.manifest.Summary.FailedAssets == 0 {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				u.logError("Failed to remove checkpoint: %v", err)
+			}
+		} else if err := u.manifest.SaveToFile(path); err != nil {
+			u.logError("Failed to save checkpoint: %v", err)
+		} else {
+			u.logError("%d files failed to upload. Retry them with: gh photos sync --resume %s", u.manifest.Summary.FailedAssets, path)
+		}
+	} else if path != "" {
+		// Save manifest if requested
+		u.logInfo("Saving manifest to %s", path)
+		if err := u.manifest.SaveToFile(path); err != nil {
 			u.logError("Failed to save manifest: %v", err)
 		}
 	}
@@ -328,9 +637,58 @@ func (u *Uploader) finalizeExecution(duration time.Duration) error {
 // filterAssets applies filters to the asset list
 func (u *Uploader) filterAssets(assets []*types.Asset) []*types.Asset {
 	var filtered []*types.Asset
-	var hiddenCount, recentlyDeletedCount, dateFilteredCount, typeFilteredCount, ignorePatternsCount int
+	var hiddenCount, recentlyDeletedCount, dateFilteredCount, typeFilteredCount, albumFilteredCount, peopleFilteredCount, nearFilteredCount, favoritesFilteredCount, burstFilteredCount, ignorePatternsCount, assetListCount int
+
+	// Default timezone for assets whose capture timezone isn't recorded (validated when the command is configured)
+	var defaultLocation *time.Location
+	if u.config.Timezone != "" {
+		loc, err := utils.ParseTimezone(u.config.Timezone)
+		if err != nil {
+			u.logError("Ignoring --timezone: %v", err)
+		} else {
+			defaultLocation = loc
+		}
+	}
+
+	// Offline gazetteer used to resolve capture locations to places
+	gazetteer := geo.Default()
+	if u.config.Gazetteer != "" {
+		loaded, err := geo.LoadGeoNames(u.config.Gazetteer)
+		if err != nil {
+			u.logError("Ignoring --gazetteer, using the embedded gazetteer: %v", err)
+		} else {
+			gazetteer = loaded
+		}
+	}
 
+	// Area assets must be captured in (validated when the command is configured)
+	var near *geo.Area
+	if u.config.Near != "" {
+		area, err := geo.ParseArea(u.config.Near)
+		if err != nil {
+			u.logError("Ignoring --near: %v", err)
+		} else {
+			near = &area
+		}
+	}
+
+	// Burst frames left out by --burst-mode, chosen among the frames that aren't hidden or deleted
+	// so an excluded frame is never the only one kept
+	var visible []*types.Asset
 	for _, asset := range assets {
+		if !asset.ShouldExclude(u.config.IncludeHidden, u.config.IncludeRecentlyDeleted) {
+			visible = append(visible, asset)
+		}
+	}
+	skippedFrames := burstFramesToSkip(visible, types.BurstMode(u.config.BurstMode))
+
+	for _, asset := range assets {
+		asset.ApplyDefaultTimezone(defaultLocation)
+		// Places Photos geocoded itself are kept; the gazetteer only fills in the rest
+		if asset.Location != nil && asset.City == "" && asset.Country == "" {
+			asset.City, asset.Country = gazetteer.Resolve(asset.Location.Latitude, asset.Location.Longitude)
+		}
+
 		// Apply exclusion rules and count what's being excluded
 		if asset.ShouldExclude(u.config.IncludeHidden, u.config.IncludeRecentlyDeleted) {
 			if asset.Flags.Hidden && !u.config.IncludeHidden {
@@ -342,12 +700,25 @@ func (u *Uploader) filterAssets(assets []*types.Asset) []*types.Asset {
 			continue
 		}
 
-		// Apply date filters
-		if u.config.StartDate != nil && asset.CreationDate.Before(*u.config.StartDate) {
+		// Apply the asset list from a backup diff
+		if u.selection != nil && !u.selection[asset.StableID()] {
+			assetListCount++
+			continue
+		}
+
+		// Apply the burst mode
+		if skippedFrames[asset] {
+			burstFilteredCount++
+			continue
+		}
+
+		// Apply date filters (inclusive, on the local day the asset was captured)
+		captureDay := asset.CaptureDay()
+		if u.config.StartDate != nil && captureDay.Before(*u.config.StartDate) {
 			dateFilteredCount++
 			continue
 		}
-		if u.config.EndDate != nil && asset.CreationDate.After(*u.config.EndDate) {
+		if u.config.EndDate != nil && captureDay.After(*u.config.EndDate) {
 			dateFilteredCount++
 			continue
 		}
@@ -356,7 +727,7 @@ func (u *Uploader) filterAssets(assets []*types.Asset) []*types.Asset {
 		if len(u.config.AssetTypes) > 0 {
 			typeMatch := false
 			for _, allowedType := range u.config.AssetTypes {
-				if strings.EqualFold(string(asset.Type), allowedType) {
+				if asset.Type.Matches(allowedType) {
 					typeMatch = true
 					break
 				}
@@ -367,19 +738,44 @@ func (u *Uploader) filterAssets(assets []*types.Asset) []*types.Asset {
 			}
 		}
 
+		// Apply album filters
+		if len(u.config.Albums) > 0 && !asset.InAnyAlbum(u.config.Albums) {
+			albumFilteredCount++
+			continue
+		}
+
+		// Apply people filters
+		if len(u.config.People) > 0 && !asset.HasAnyPerson(u.config.People) {
+			peopleFilteredCount++
+			continue
+		}
+
+		// Apply the favorites filter
+		if u.config.FavoritesOnly && !asset.Flags.Favorite {
+			favoritesFilteredCount++
+			continue
+		}
+
+		// Apply the location filter; assets without a location are never near
+		if near != nil && (asset.Location == nil || !near.Contains(asset.Location.Latitude, asset.Location.Longitude)) {
+			nearFilteredCount++
+			continue
+		}
+
 		// Apply ignore patterns - check both source path and filename
 		if len(u.config.IgnorePatterns) > 0 {
 			shouldIgnore := false
+			matchPath := asset.MatchPath()
 			for _, pattern := range u.config.IgnorePatterns {
 				// Check if pattern matches the filename directly
-				if matched, _ := filepath.Match(pattern, filepath.Base(asset.SourcePath)); matched {
+				if matched, _ := filepath.Match(pattern, filepath.Base(matchPath)); matched {
 					shouldIgnore = true
 					break
 				}
 
 				// Check if pattern is a simple directory name (exact substring match)
 				if !strings.Contains(pattern, "*") && !strings.Contains(pattern, "?") {
-					if strings.Contains(asset.SourcePath, pattern) {
+					if strings.Contains(matchPath, pattern) {
 						shouldIgnore = true
 						break
 					}
@@ -388,13 +784,13 @@ func (u *Uploader) filterAssets(assets []*types.Asset) []*types.Asset {
 					// For patterns like "Thumbnails/*", check if any directory in the path matches
 					if strings.HasSuffix(pattern, "/*") {
 						dirPattern := strings.TrimSuffix(pattern, "/*")
-						if strings.Contains(asset.SourcePath, "/"+dirPattern+"/") {
+						if strings.Contains(matchPath, "/"+dirPattern+"/") {
 							shouldIgnore = true
 							break
 						}
 					} else {
 						// For other wildcard patterns, check each path component
-						pathParts := strings.Split(filepath.Dir(asset.SourcePath), string(filepath.Separator))
+						pathParts := strings.Split(filepath.Dir(matchPath), string(filepath.Separator))
 						for _, part := range pathParts {
 							if matched, _ := filepath.Match(pattern, part); matched {
 								shouldIgnore = true
@@ -418,7 +814,18 @@ func (u *Uploader) filterAssets(assets []*types.Asset) []*types.Asset {
 		if granularity == "" {
 			granularity = types.GranularityDay
 		}
-		asset.TargetPath = asset.GenerateTargetPath(granularity)
+		if u.config.PathTemplate != "" {
+			asset.TargetPath = asset.GenerateTemplatedTargetPath(u.config.PathTemplate, types.PathTemplateOptions{
+				DeviceName:       u.config.DeviceName,
+				PreferredAlbums:  u.config.Albums,
+				PreferredPeople:  u.config.People,
+				FilenameTemplate: u.config.FilenameTemplate,
+			})
+		} else if u.config.OrganizeByAlbum {
+			asset.TargetPath = asset.GenerateAlbumTargetPath(granularity, u.config.FilenameTemplate, u.config.Albums)
+		} else {
+			asset.TargetPath = asset.GenerateTargetPath(granularity, u.config.FilenameTemplate)
+		}
 
 		filtered = append(filtered, asset)
 	}
@@ -436,13 +843,111 @@ func (u *Uploader) filterAssets(assets []*types.Asset) []*types.Asset {
 	if typeFilteredCount > 0 {
 		u.logInfo("Excluding %d assets due to type filters", typeFilteredCount)
 	}
+	if albumFilteredCount > 0 {
+		u.logInfo("Excluding %d assets due to album filters", albumFilteredCount)
+	}
+	if peopleFilteredCount > 0 {
+		u.logInfo("Excluding %d assets due to person filters", peopleFilteredCount)
+	}
+	if burstFilteredCount > 0 {
+		u.logInfo("Excluding %d burst frames due to --burst-mode %s", burstFilteredCount, u.config.BurstMode)
+	}
+	if favoritesFilteredCount > 0 {
+		u.logInfo("Excluding %d assets that aren't favorites", favoritesFilteredCount)
+	}
+	if nearFilteredCount > 0 {
+		u.logInfo("Excluding %d assets captured outside --near (or without a location)", nearFilteredCount)
+	}
 	if ignorePatternsCount > 0 {
 		u.logInfo("Excluding %d assets due to ignore patterns", ignorePatternsCount)
 	}
+	if assetListCount > 0 {
+		u.logInfo("Excluding %d assets not added or edited in the asset list", assetListCount)
+	}
 
 	return filtered
 }
 
+// burstFramesToSkip returns the burst frames the burst mode leaves out. The picked mode keeps the
+// frames the user selected and the key mode keeps the key frame. A burst without such frames keeps
+// its key frame, or its first frame when none is marked, so a burst is never dropped entirely.
+func burstFramesToSkip(assets []*types.Asset, mode types.BurstMode) map[*types.Asset]bool {
+	if mode == "" || mode == types.BurstModeAll {
+		return nil
+	}
+
+	bursts := make(map[string][]*types.Asset)
+	var order []string
+	for _, asset := range assets {
+		if asset.Flags.BurstID == nil {
+			continue
+		}
+		id := *asset.Flags.BurstID
+		if _, ok := bursts[id]; !ok {
+			order = append(order, id)
+		}
+		bursts[id] = append(bursts[id], asset)
+	}
+
+	skip := make(map[*types.Asset]bool)
+	for _, id := range order {
+		frames := bursts[id]
+
+		keep := make(map[*types.Asset]bool)
+		if mode == types.BurstModePicked {
+			for _, frame := range frames {
+				if frame.Flags.BurstPick.Has(types.BurstPickUser) {
+					keep[frame] = true
+				}
+			}
+		}
+		if len(keep) == 0 {
+			keep[keyFrame(frames)] = true
+		}
+
+		for _, frame := range frames {
+			if !keep[frame] {
+				skip[frame] = true
+			}
+		}
+	}
+	return skip
+}
+
+// keyFrame returns the frame marked as the key frame of a burst, or the earliest frame when none is
+func keyFrame(frames []*types.Asset) *types.Asset {
+	first := frames[0]
+	for _, frame := range frames {
+		if frame.Flags.BurstPick.Has(types.BurstPickKey) {
+			return frame
+		}
+		if frame.CreationDate.Before(first.CreationDate) {
+			first = frame
+		}
+	}
+	return first
+}
+
+// loadAssetList loads the assets selected by a diff between two backups
+func (u *Uploader) loadAssetList() error {
+	if u.config.AssetList == "" {
+		return nil
+	}
+	result, err := diff.LoadFromFile(u.config.AssetList)
+	if err != nil {
+		return fmt.Errorf("failed to load --asset-list: %w", err)
+	}
+	u.selection = result.SyncSelection()
+	return nil
+}
+
+// FilterAssets applies the sync filters (hidden, recently deleted, dates, types, albums, ignore patterns)
+// and computes target paths without creating an uploader, so other commands see exactly what sync would upload
+func FilterAssets(config Config, assets []*types.Asset, log *logger.Logger) []*types.Asset {
+	u := &Uploader{config: config, logger: log}
+	return u.filterAssets(assets)
+}
+
 // computeChecksums calculates checksums for all assets
 func (u *Uploader) computeChecksums(assets []*types.Asset) error {
 	for i, asset := range assets {
@@ -451,7 +956,7 @@ func (u *Uploader) computeChecksums(assets []*types.Asset) error {
 				filepath.Base(asset.SourcePath), i+1, len(assets))
 		}
 
-		if err := asset.ComputeChecksum(); err != nil {
+		if err := u.computeChecksum(asset); err != nil {
 			u.logError("Failed to compute checksum for %s: %v", asset.SourcePath, err)
 			continue
 		}
@@ -459,6 +964,19 @@ func (u *Uploader) computeChecksums(assets []*types.Asset) error {
 	return nil
 }
 
+// computeChecksum computes the checksum of an asset, decrypting its file for as long as it is read
+func (u *Uploader) computeChecksum(asset *types.Asset) error {
+	paths := []string{asset.SourcePath}
+	if err := u.parser.PrepareFiles(paths); err != nil {
+		return err
+	}
+	err := asset.ComputeChecksum()
+	if releaseErr := u.parser.ReleaseFiles(paths); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
 // uploadProgressCallback provides real-time upload progress updates
 func (u *Uploader) uploadProgressCallback(completed, total int, currentFile string) {
 	percentage := float64(completed) / float64(total) * 100
@@ -478,8 +996,14 @@ func (u *Uploader) uploadProgressCallback(completed, total int, currentFile stri
 }
 
 // updateManifestCallback updates the manifest when an upload completes
+// The index is relative to the entries passed to UploadBatch.
 func (u *Uploader) updateManifestCallback(index int, status manifest.OperationStatus, errorMsg string) {
+	if index < 0 || index >= len(u.uploadIndexes) {
+		return
+	}
+	index = u.uploadIndexes[index]
 	u.manifest.UpdateEntry(index, status, errorMsg)
+	u.saveCheckpoint(false)
 
 	if u.config.Verbose {
 		entry := u.manifest.Entries[index]
@@ -491,6 +1015,35 @@ func (u *Uploader) updateManifestCallback(index int, status manifest.OperationSt
 	}
 }
 
+// manifestPath returns where the manifest is saved: the --save-manifest path, the resumed
+// manifest itself when resuming without --save-manifest, or otherwise the default checkpoint
+func (u *Uploader) manifestPath() string {
+	if u.config.SaveManifest != "" {
+		return u.config.SaveManifest
+	}
+	if u.config.Resume != "" {
+		return u.config.Resume
+	}
+	return u.checkpointPath
+}
+
+// saveCheckpoint writes the manifest so a crash or interrupt leaves an accurate record of
+// what was uploaded. Unless forced, writes are throttled to one per checkpointInterval.
+func (u *Uploader) saveCheckpoint(force bool) {
+	path := u.manifestPath()
+	if path == "" || u.config.DryRun {
+		return
+	}
+	if !force && time.Since(u.lastCheckpoint) < checkpointInterval {
+		return
+	}
+	if err := u.manifest.SaveToFile(path); err != nil {
+		u.logError("Failed to write manifest checkpoint: %v", err)
+		return
+	}
+	u.lastCheckpoint = time.Now()
+}
+
 // verifyUploads verifies that uploaded files match the source
 func (u *Uploader) verifyUploads(ctx context.Context) error {
 	uploadedEntries := u.manifest.GetFilteredEntries(manifest.StatusUploaded)
@@ -501,7 +1054,7 @@ func (u *Uploader) verifyUploads(ctx context.Context) error {
 				filepath.Base(entry.SourcePath), i+1, len(uploadedEntries))
 		}
 
-		if err := u.rcloneClient.VerifyUpload(ctx, entry); err != nil {
+		if err := u.verifyUpload(ctx, entry); err != nil {
 			u.logError("Verification failed for %s: %v", entry.SourcePath, err)
 			// Update manifest status
 			for j, manifestEntry := range u.manifest.Entries {
@@ -524,6 +1077,19 @@ func (u *Uploader) verifyUploads(ctx context.Context) error {
 	return nil
 }
 
+// verifyUpload verifies a single upload, decrypting its source file for as long as it is checked
+func (u *Uploader) verifyUpload(ctx context.Context, entry manifest.Entry) error {
+	paths := []string{entry.SourcePath}
+	if err := u.parser.PrepareFiles(paths); err != nil {
+		return err
+	}
+	err := u.rcloneClient.VerifyUpload(ctx, entry)
+	if releaseErr := u.parser.ReleaseFiles(paths); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
 // Logging methods
 func (u *Uploader) logInfo(format string, args ...interface{}) {
 	if u.config.Verbose || u.config.LogLevel == "debug" || u.config.LogLevel == "info" {
@@ -566,6 +1132,21 @@ func (u *Uploader) setupAuditTrail() error {
 		Checksum:               u.config.ComputeChecksums,
 		IgnorePatterns:         u.config.IgnorePatterns,
 		PathGranularity:        u.config.PathGranularity,
+		Albums:                 u.config.Albums,
+		People:                 u.config.People,
+		OrganizeByAlbum:        u.config.OrganizeByAlbum,
+		LivePhotoMode:          u.config.LivePhotoMode,
+		BurstMode:              u.config.BurstMode,
+		EditsMode:              u.config.EditsMode,
+		Timezone:               u.config.Timezone,
+		FilenameTemplate:       u.config.FilenameTemplate,
+		PathTemplate:           u.config.PathTemplate,
+		RemotePreScan:          u.config.RemotePreScan,
+		AssetList:              u.config.AssetList,
+		Near:                   u.config.Near,
+		Gazetteer:              u.config.Gazetteer,
+		FavoritesOnly:          u.config.FavoritesOnly,
+		FavoritesRemote:        u.config.FavoritesRemote,
 	}
 
 	u.auditTrail.SetInvocation(u.config.Remote, flags)
@@ -587,7 +1168,19 @@ func (u *Uploader) finalizeAuditTrail() error {
 		asset := u.findAssetBySourcePath(entry.SourcePath)
 		if asset != nil {
 			status := u.manifestStatusToAuditStatus(entry.Status)
-			u.auditTrail.AddAsset(asset, entry.TargetPath, status)
+			if entry.Edited {
+				u.auditTrail.AddEditedRender(asset, entry.TargetPath, status)
+			} else if entry.LivePhotoRole != "" {
+				u.auditTrail.AddLivePhotoComponent(asset, audit.LivePhotoComponent{
+					Role:             entry.LivePhotoRole,
+					LocalPath:        entry.SourcePath,
+					RemotePath:       entry.TargetPath,
+					PairedRemotePath: entry.PairedPath,
+					SizeBytes:        entry.FileSize,
+				}, status)
+			} else {
+				u.auditTrail.AddAsset(asset, entry.TargetPath, status)
+			}
 		}
 	}
 
@@ -610,9 +1203,12 @@ func (u *Uploader) finalizeAuditTrail() error {
 }
 
 // findAssetBySourcePath finds an asset by its source path (helper for audit trail)
+// Live Photo videos and edited renders are matched through their original's asset.
 func (u *Uploader) findAssetBySourcePath(sourcePath string) *types.Asset {
 	for _, asset := range u.filteredAssets {
-		if asset.SourcePath == sourcePath {
+		if asset.SourcePath == sourcePath ||
+			(asset.LivePhotoVideoPath != "" && asset.LivePhotoVideoPath == sourcePath) ||
+			(asset.EditedPath != "" && asset.EditedPath == sourcePath) {
 			return asset
 		}
 	}
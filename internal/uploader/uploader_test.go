@@ -1,9 +1,14 @@
 package uploader
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/grantbirki/gh-photos/internal/diff"
+	"github.com/grantbirki/gh-photos/internal/manifest"
 	"github.com/grantbirki/gh-photos/internal/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -144,3 +149,380 @@ func TestFilterAssetsIgnorePatternsWithWildcards(t *testing.T) {
 	assert.Len(t, filtered, 1)
 	assert.Equal(t, "1", filtered[0].ID)
 }
+
+func TestFilterAssetsByAlbum(t *testing.T) {
+	config := Config{
+		Albums:          []string{"Family"},
+		OrganizeByAlbum: true,
+		PathGranularity: "year",
+	}
+	uploader := &Uploader{config: config}
+
+	creationDate := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	assets := []*types.Asset{
+		{
+			ID:           "1",
+			SourcePath:   "/extracted/CameraRollDomain/Media/DCIM/100APPLE/IMG_0001.HEIC",
+			Filename:     "IMG_0001.HEIC",
+			Type:         types.AssetTypePhoto,
+			CreationDate: creationDate,
+			Albums:       []string{"Trips", "Family"},
+		},
+		{
+			ID:           "2",
+			SourcePath:   "/extracted/CameraRollDomain/Media/DCIM/100APPLE/IMG_0002.HEIC",
+			Filename:     "IMG_0002.HEIC",
+			Type:         types.AssetTypePhoto,
+			CreationDate: creationDate,
+			Albums:       []string{"Trips"},
+		},
+		{
+			ID:           "3",
+			SourcePath:   "/extracted/CameraRollDomain/Media/DCIM/100APPLE/IMG_0003.HEIC",
+			Filename:     "IMG_0003.HEIC",
+			Type:         types.AssetTypePhoto,
+			CreationDate: creationDate,
+		},
+	}
+
+	filtered := uploader.filterAssets(assets)
+
+	// Only the asset in the Family album remains, placed under the preferred album folder
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "1", filtered[0].ID)
+	assert.Equal(t, "Family/2024/photos/IMG_0001.HEIC", filtered[0].TargetPath)
+}
+
+func TestFilterAssetsByPerson(t *testing.T) {
+	config := Config{
+		People:       []string{"Grandma"},
+		PathTemplate: "{person}/{year}",
+	}
+	uploader := &Uploader{config: config}
+
+	creationDate := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	assets := []*types.Asset{
+		{ID: "1", Filename: "IMG_0001.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate, People: []string{"Biscuit", "Grandma"}},
+		{ID: "2", Filename: "IMG_0002.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate, People: []string{"Biscuit"}},
+		{ID: "3", Filename: "IMG_0003.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate},
+	}
+
+	filtered := uploader.filterAssets(assets)
+
+	// Only the photo of Grandma remains, placed under her folder even though Biscuit is listed first
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "1", filtered[0].ID)
+	assert.Equal(t, "Grandma/2024/IMG_0001.HEIC", filtered[0].TargetPath)
+}
+
+func TestFilterAssetsFavoritesOnly(t *testing.T) {
+	uploader := &Uploader{config: Config{FavoritesOnly: true}}
+
+	creationDate := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	assets := []*types.Asset{
+		{ID: "1", Filename: "IMG_0001.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate, Flags: types.AssetFlags{Favorite: true}},
+		{ID: "2", Filename: "IMG_0002.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate},
+	}
+
+	filtered := uploader.filterAssets(assets)
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "1", filtered[0].ID)
+}
+
+func TestFilterAssetsMediaSubtypes(t *testing.T) {
+	creationDate := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	assets := []*types.Asset{
+		{ID: "1", Filename: "IMG_0001.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate},
+		{ID: "2", Filename: "IMG_0002.HEIC", Type: types.AssetTypePortrait, CreationDate: creationDate},
+		{ID: "3", Filename: "IMG_0003.MOV", Type: types.AssetTypeSlomo, CreationDate: creationDate},
+	}
+
+	// photos still selects its subtypes
+	filtered := (&Uploader{config: Config{AssetTypes: []string{"photos"}}}).filterAssets(assets)
+	assert.Len(t, filtered, 2)
+
+	// a subtype selects only itself
+	filtered = (&Uploader{config: Config{AssetTypes: []string{"slomo", "Portrait"}}}).filterAssets(assets)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "2", filtered[0].ID)
+		assert.Equal(t, "3", filtered[1].ID)
+	}
+}
+
+func TestFilterAssetsBurstMode(t *testing.T) {
+	day := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	frame := func(id, burstID string, pick types.BurstPick, offset time.Duration) *types.Asset {
+		return &types.Asset{
+			ID:           id,
+			Filename:     "IMG_" + id + ".JPG",
+			Type:         types.AssetTypeBurst,
+			CreationDate: day.Add(offset),
+			Flags:        types.AssetFlags{Burst: true, BurstID: &burstID, BurstPick: pick},
+		}
+	}
+	assets := func() []*types.Asset {
+		return []*types.Asset{
+			// Burst A: the user kept two frames
+			frame("1", "A", types.BurstPickNotSelected|types.BurstPickKey, 0),
+			frame("2", "A", types.BurstPickUser, time.Second),
+			frame("3", "A", types.BurstPickUser|types.BurstPickAuto, 2*time.Second),
+			// Burst B: never reviewed
+			frame("4", "B", types.BurstPickNotSelected, 0),
+			frame("5", "B", types.BurstPickAuto|types.BurstPickKey, time.Second),
+			// Burst C: no pick types recorded
+			frame("6", "C", 0, time.Second),
+			frame("7", "C", 0, 0),
+			{ID: "8", Filename: "IMG_8.HEIC", Type: types.AssetTypePhoto, CreationDate: day},
+		}
+	}
+	ids := func(filtered []*types.Asset) []string {
+		var result []string
+		for _, asset := range filtered {
+			result = append(result, asset.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		mode     string
+		expected []string
+	}{
+		{mode: "", expected: []string{"1", "2", "3", "4", "5", "6", "7", "8"}},
+		{mode: "all", expected: []string{"1", "2", "3", "4", "5", "6", "7", "8"}},
+		{mode: "picked", expected: []string{"2", "3", "5", "7", "8"}},
+		{mode: "key", expected: []string{"1", "5", "7", "8"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			uploader := &Uploader{config: Config{BurstMode: tt.mode}}
+			assert.Equal(t, tt.expected, ids(uploader.filterAssets(assets())))
+		})
+	}
+
+	// The {burst} token groups the frames of a burst in a folder named after the burst ID
+	uploader := &Uploader{config: Config{BurstMode: "picked", PathTemplate: "{year}/{month}/{day}/{type}/{burst}"}}
+	filtered := uploader.filterAssets(assets())
+	assert.Equal(t, "2024/06/01/burst/A/IMG_2.JPG", filtered[0].TargetPath)
+}
+
+func TestFilterAssetsBurstModeSkipsExcludedFrames(t *testing.T) {
+	day := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	burstID := "A"
+	frame := func(id string, pick types.BurstPick, offset time.Duration, deleted bool) *types.Asset {
+		return &types.Asset{
+			ID:           id,
+			Filename:     "IMG_" + id + ".JPG",
+			Type:         types.AssetTypeBurst,
+			CreationDate: day.Add(offset),
+			Flags:        types.AssetFlags{Burst: true, BurstID: &burstID, BurstPick: pick, RecentlyDeleted: deleted},
+		}
+	}
+
+	// The key frame was moved to Recently Deleted, so another frame stands in for the burst
+	for _, mode := range []string{"key", "picked"} {
+		t.Run(mode, func(t *testing.T) {
+			assets := []*types.Asset{
+				frame("1", types.BurstPickNotSelected, 0, false),
+				frame("2", types.BurstPickAuto|types.BurstPickKey, time.Second, true),
+				frame("3", types.BurstPickNotSelected, 2*time.Second, false),
+			}
+			uploader := &Uploader{config: Config{BurstMode: mode}}
+			filtered := uploader.filterAssets(assets)
+			if assert.Len(t, filtered, 1) {
+				assert.Equal(t, "1", filtered[0].ID)
+			}
+		})
+	}
+}
+
+func TestFavoriteIndexes(t *testing.T) {
+	favorite := types.AssetFlags{Favorite: true}
+	uploader := &Uploader{manifest: &manifest.Manifest{Entries: []manifest.Entry{
+		{SourcePath: "/a", Status: manifest.StatusUploaded, Flags: favorite},
+		{SourcePath: "/b", Status: manifest.StatusUploaded},
+		{SourcePath: "/c", Status: manifest.StatusFailed, Flags: favorite},
+		{SourcePath: "/d", Status: manifest.StatusSkipped, Flags: favorite},
+		{SourcePath: "/e", Status: manifest.StatusVerified, Flags: favorite, FavoritesStatus: manifest.StatusUploaded},
+		{SourcePath: "/f", Status: manifest.StatusUploaded, Flags: favorite, FavoritesStatus: manifest.StatusFailed},
+	}}}
+
+	// Favorites already on the main remote are mirrored, unless they're already on the favorites remote
+	assert.Equal(t, []int{0, 3, 5}, uploader.favoriteIndexes())
+}
+
+func TestFilterAssetsNear(t *testing.T) {
+	config := Config{
+		Near:         "39.7392,-104.9903,50",
+		PathTemplate: "{country}/{city}",
+	}
+	uploader := &Uploader{config: config}
+
+	creationDate := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	assets := []*types.Asset{
+		{ID: "1", Filename: "IMG_0001.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate,
+			Location: &types.Location{Latitude: 39.7392, Longitude: -104.9903}}, // Denver
+		{ID: "2", Filename: "IMG_0002.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate,
+			Location: &types.Location{Latitude: 48.8584, Longitude: 2.2945}}, // Paris
+		{ID: "3", Filename: "IMG_0003.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate},
+		{ID: "4", Filename: "IMG_0004.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate,
+			Location: &types.Location{Latitude: 39.6133, Longitude: -105.0166},
+			City:     "Littleton", Country: "United States"}, // geocoded by Photos
+	}
+
+	filtered := uploader.filterAssets(assets)
+
+	// Only the photos taken near Denver remain; the one without a location is never near
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "1", filtered[0].ID)
+		assert.Equal(t, "United States/Denver/IMG_0001.HEIC", filtered[0].TargetPath)
+		assert.Equal(t, "United States/Littleton/IMG_0004.HEIC", filtered[1].TargetPath)
+	}
+
+	// Places are resolved for every asset with a location, filtered or not
+	assert.Equal(t, "Paris", assets[1].City)
+	assert.Equal(t, "France", assets[1].Country)
+	assert.Empty(t, assets[2].Country)
+}
+
+func TestFilterAssetsUsesCaptureDay(t *testing.T) {
+	startDate := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+	config := Config{
+		StartDate: &startDate,
+		EndDate:   &endDate,
+		Timezone:  "America/Denver",
+	}
+	uploader := &Uploader{config: config}
+
+	// 9pm in Denver on March 18th is already March 19th in UTC
+	evening := &types.Asset{
+		ID:           "1",
+		SourcePath:   "/extracted/CameraRollDomain/Media/DCIM/100APPLE/IMG_0001.HEIC",
+		Filename:     "IMG_0001.HEIC",
+		Type:         types.AssetTypePhoto,
+		CreationDate: time.Date(2024, 3, 19, 3, 0, 0, 0, time.UTC),
+	}
+	evening.SetCaptureTimezone("GMT-0600", -6*3600)
+
+	// No recorded capture timezone: falls back to --timezone (Denver is UTC-6 in March)
+	noTimezone := &types.Asset{
+		ID:           "2",
+		SourcePath:   "/extracted/CameraRollDomain/Media/DCIM/100APPLE/IMG_0002.HEIC",
+		Filename:     "IMG_0002.HEIC",
+		Type:         types.AssetTypePhoto,
+		CreationDate: time.Date(2024, 3, 19, 5, 30, 0, 0, time.UTC),
+	}
+
+	// The next morning in local time is outside the range
+	nextDay := &types.Asset{
+		ID:           "3",
+		SourcePath:   "/extracted/CameraRollDomain/Media/DCIM/100APPLE/IMG_0003.HEIC",
+		Filename:     "IMG_0003.HEIC",
+		Type:         types.AssetTypePhoto,
+		CreationDate: time.Date(2024, 3, 19, 15, 0, 0, 0, time.UTC),
+	}
+	nextDay.SetCaptureTimezone("GMT-0600", -6*3600)
+
+	filtered := uploader.filterAssets([]*types.Asset{evening, noTimezone, nextDay})
+
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "2024/03/18/photos/IMG_0001.HEIC", filtered[0].TargetPath)
+		assert.Equal(t, "2024/03/18/photos/IMG_0002.HEIC", filtered[1].TargetPath)
+	}
+}
+
+func TestFilterAssetsByAssetList(t *testing.T) {
+	assetList := filepath.Join(t.TempDir(), "changes.json")
+	result := &diff.Result{Changes: []diff.Change{
+		{Change: diff.ChangeAdded, Identity: "UUID-NEW"},
+		{Change: diff.ChangeEdited, Identity: "UUID-EDITED"},
+		{Change: diff.ChangeHidden, Identity: "UUID-HIDDEN"},
+	}}
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(assetList, data, 0644))
+
+	uploader := &Uploader{config: Config{AssetList: assetList}}
+	assert.NoError(t, uploader.loadAssetList())
+
+	creationDate := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	assets := []*types.Asset{
+		{ID: "1", UUID: "UUID-NEW", Filename: "IMG_0001.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate},
+		{ID: "2", UUID: "UUID-EDITED", Filename: "IMG_0002.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate},
+		{ID: "3", UUID: "UUID-HIDDEN", Filename: "IMG_0003.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate},
+		{ID: "4", UUID: "UUID-UNCHANGED", Filename: "IMG_0004.HEIC", Type: types.AssetTypePhoto, CreationDate: creationDate},
+	}
+
+	filtered := uploader.filterAssets(assets)
+
+	// Only added and edited assets are synced
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "1", filtered[0].ID)
+		assert.Equal(t, "2", filtered[1].ID)
+	}
+
+	// An unreadable list is an error rather than selecting every asset
+	missing := &Uploader{config: Config{AssetList: filepath.Join(t.TempDir(), "missing.json")}}
+	assert.ErrorContains(t, missing.loadAssetList(), "failed to load --asset-list")
+	assert.Nil(t, missing.selection)
+}
+
+func TestUpdateManifestCallbackCheckpoints(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	uploader := &Uploader{
+		config: Config{SaveManifest: manifestPath},
+		manifest: &manifest.Manifest{
+			RemoteTarget: "gdrive:Photos",
+			Entries: []manifest.Entry{
+				{SourcePath: "/test/file1.jpg", Status: manifest.StatusUploaded},
+				{SourcePath: "/test/file2.jpg", Status: manifest.StatusPending},
+				{SourcePath: "/test/file3.jpg", Status: manifest.StatusPending},
+			},
+		},
+		// Only the last two entries were handed to UploadBatch
+		uploadIndexes: []int{1, 2},
+	}
+
+	// Batch index 1 is manifest entry 2
+	uploader.updateManifestCallback(1, manifest.StatusUploaded, "")
+
+	assert.Equal(t, manifest.StatusPending, uploader.manifest.Entries[1].Status)
+	assert.Equal(t, manifest.StatusUploaded, uploader.manifest.Entries[2].Status)
+
+	// The first update is checkpointed to disk immediately
+	saved, err := manifest.LoadFromFile(manifestPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, manifest.StatusUploaded, saved.Entries[2].Status)
+		assert.Equal(t, 1, saved.ResetIncomplete())
+	}
+}
+
+func TestDefaultCheckpoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := defaultCheckpointPath()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, path, filepath.Join("gh-photos", "checkpoints", "sync-"))
+
+	// Without --save-manifest or --resume, checkpoints go to the default path
+	uploader := &Uploader{
+		checkpointPath: path,
+		manifest: &manifest.Manifest{
+			RemoteTarget: "gdrive:Photos",
+			Entries:      []manifest.Entry{{SourcePath: "/test/file1.jpg", Status: manifest.StatusUploaded}},
+		},
+	}
+	assert.Equal(t, path, uploader.manifestPath())
+	uploader.saveCheckpoint(true)
+
+	saved, err := manifest.LoadFromFile(path)
+	if assert.NoError(t, err) {
+		assert.Len(t, saved.Entries, 1)
+	}
+
+	// An explicit manifest path takes precedence
+	uploader.config.SaveManifest = "sync-manifest.json"
+	assert.Equal(t, "sync-manifest.json", uploader.manifestPath())
+}
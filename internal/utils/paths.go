@@ -21,6 +21,11 @@ func (bp *BackupPaths) ExtractionMetadata() string {
 	return filepath.Join(bp.backupPath, "extraction-metadata.json")
 }
 
+// ExtractionJournal returns the path to the journal of files completed by an extraction
+func (bp *BackupPaths) ExtractionJournal() string {
+	return filepath.Join(bp.backupPath, "extraction-journal.log")
+}
+
 // ManifestPlist returns the path to the Manifest.plist file
 func (bp *BackupPaths) ManifestPlist() string {
 	return filepath.Join(bp.backupPath, "Manifest.plist")
@@ -18,6 +18,11 @@ func TestBackupPaths(t *testing.T) {
 			method:   (*BackupPaths).ExtractionMetadata,
 			expected: filepath.Join("/test/backup", "extraction-metadata.json"),
 		},
+		{
+			name:     "ExtractionJournal",
+			method:   (*BackupPaths).ExtractionJournal,
+			expected: filepath.Join("/test/backup", "extraction-journal.log"),
+		},
 		{
 			name:     "ManifestPlist",
 			method:   (*BackupPaths).ManifestPlist,
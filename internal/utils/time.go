@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// utcOffsetPattern matches fixed UTC offsets such as +02:00, -0700, UTC+5:30 or GMT-3
+var utcOffsetPattern = regexp.MustCompile(`^(?i:utc|gmt)?([+-])(\d{1,2}):?(\d{2})?$`)
+
+// ParseTimezone resolves a user-provided timezone into a location
+// Accepts IANA names (e.g. "America/Denver"), "Local", "UTC", and fixed offsets (e.g. "-07:00", "UTC+5:30")
+func ParseTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("timezone is empty")
+	}
+
+	if match := utcOffsetPattern.FindStringSubmatch(name); match != nil {
+		hours, _ := strconv.Atoi(match[2])
+		minutes := 0
+		if match[3] != "" {
+			minutes, _ = strconv.Atoi(match[3])
+		}
+		if hours > 14 || minutes > 59 {
+			return nil, fmt.Errorf("invalid UTC offset '%s'", name)
+		}
+
+		offset := hours*3600 + minutes*60
+		if match[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(fmt.Sprintf("UTC%s%02d:%02d", match[1], hours, minutes), offset), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone '%s': %w", name, err)
+	}
+	return loc, nil
+}
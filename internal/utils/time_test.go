@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimezone(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedOffset int // seconds east of UTC on 2024-01-15
+		expectError    bool
+	}{
+		{name: "UTC", input: "UTC", expectedOffset: 0},
+		{name: "IANA name", input: "America/Denver", expectedOffset: -7 * 3600},
+		{name: "offset with colon", input: "-07:00", expectedOffset: -7 * 3600},
+		{name: "offset without colon", input: "+0530", expectedOffset: 5*3600 + 30*60},
+		{name: "prefixed offset", input: "UTC+2", expectedOffset: 2 * 3600},
+		{name: "empty", input: "  ", expectError: true},
+		{name: "unknown name", input: "Mars/Olympus_Mons", expectError: true},
+		{name: "offset out of range", input: "+25:00", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := ParseTimezone(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, offset := time.Date(2024, 1, 15, 12, 0, 0, 0, loc).Zone()
+			assert.Equal(t, tt.expectedOffset, offset)
+		})
+	}
+}